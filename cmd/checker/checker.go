@@ -2,6 +2,8 @@ package main
 
 import (
 	"bufio"
+	"bytes"
+	"cmp"
 	"flag"
 	"fmt"
 	"log"
@@ -9,9 +11,18 @@ import (
 	"slices"
 	"strings"
 
+	"push-swap-go/internal/batch"
+	"push-swap-go/internal/cli"
 	"push-swap-go/internal/pushswap"
 )
 
+// Exit codes used by the -compat output mode.
+const (
+	exitOK    = 0 // The numbers were sorted by the instructions.
+	exitKO    = 1 // The instructions did not sort the numbers.
+	exitError = 2 // The numbers or instructions could not be read.
+)
+
 type filePair struct {
 	instructionsFile string
 	numbersFile      string
@@ -44,41 +55,41 @@ func (f *filePairs) Set(value string) error {
 }
 
 func printHelp() {
-	fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [options] numbers...", os.Args[0])
-	fmt.Fprintf(flag.CommandLine.Output(), "\tReads a list of instructions from stdin to sort a list of numbers given")
-	fmt.Fprintf(flag.CommandLine.Output(), "\tvia the command line by default.")
+	fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [options] numbers...\n", os.Args[0])
+	fmt.Fprintf(flag.CommandLine.Output(), "\tReads a list of instructions from stdin to sort a list of numbers given\n")
+	fmt.Fprintf(flag.CommandLine.Output(), "\tvia the command line by default.\n")
 	fmt.Fprintln(flag.CommandLine.Output())
 	flag.PrintDefaults()
-}
-
-func readNumbers(file string, allowDups bool) ([]float64, error) {
-	input, err := os.Open(file)
-	if err != nil {
-		return nil, fmt.Errorf("opening file: %v", err)
-	}
-	defer input.Close()
-
-	var numStrings []string
-	inputScanner := bufio.NewScanner(input)
-
-	for inputScanner.Scan() {
-		numStrings = append(numStrings, strings.Fields(inputScanner.Text())...)
-	}
-
-	err = inputScanner.Err()
-	if err != nil {
-		return nil, fmt.Errorf("reading file: %v", err)
+	fmt.Fprintln(flag.CommandLine.Output())
+	fmt.Fprintf(flag.CommandLine.Output(), "Exit codes with -compat, -json or -grade:\n")
+	fmt.Fprintf(flag.CommandLine.Output(), "\t%d\tOK, every input was sorted\n", exitOK)
+	fmt.Fprintf(flag.CommandLine.Output(), "\t%d\tKO, at least one input was not sorted\n", exitKO)
+	fmt.Fprintf(flag.CommandLine.Output(), "\t%d\tError, at least one input could not be read\n", exitError)
+	fmt.Fprintln(flag.CommandLine.Output())
+	fmt.Fprintf(flag.CommandLine.Output(), "Grading scores one point for every limit the instruction count is below, using\n")
+	fmt.Fprintf(flag.CommandLine.Output(), "the smallest size in the table that is at least the number of inputs. Default table:\n")
+	for _, th := range defaultThresholds {
+		table := thresholdTable{th}
+		fmt.Fprintf(flag.CommandLine.Output(), "\t%s\n", table.String())
 	}
+}
 
-	numbers, err := pushswap.ParseNumberSlice(numStrings, allowDups)
-	if err != nil {
-		return nil, err
-	}
+// commandError reports a line of an instructions file that holds no
+// instruction.
+type commandError struct {
+	index   int // Index the instruction would have had in the list.
+	line    int
+	command string
+}
 
-	return numbers, nil
+func (e *commandError) Error() string {
+	return fmt.Sprintf("line %d: unrecognised command %q", e.line, e.command)
 }
 
-func readInstructions(file string) ([]pushswap.Operation, error) {
+// readInstructions reads one instruction per line. In strict mode a line must
+// be exactly an instruction name, otherwise surrounding whitespace, blank
+// lines and lines starting with '#' are ignored.
+func readInstructions(file string, strict bool) ([]pushswap.Operation, error) {
 	var input *os.File
 
 	if file == "-" {
@@ -95,19 +106,25 @@ func readInstructions(file string) ([]pushswap.Operation, error) {
 	var instructions []pushswap.Operation
 	inputScanner := bufio.NewScanner(input)
 
-	for inputScanner.Scan() {
-		op := pushswap.Operation(strings.Fields(inputScanner.Text())[0])
-
-		switch op {
-		case pushswap.Invalid:
-		case pushswap.PA, pushswap.PB, pushswap.RA,
-			pushswap.RB, pushswap.RR, pushswap.RRA,
-			pushswap.RRB, pushswap.RRR, pushswap.SA,
-			pushswap.SB, pushswap.SS:
-			instructions = append(instructions, pushswap.Operation(op))
-		default:
-			return nil, fmt.Errorf("unrecognised command %q", op)
+	for line := 1; inputScanner.Scan(); line++ {
+		name := inputScanner.Text()
+
+		if !strict {
+			// Comments are written by `push-swap -explain comments`.
+			fields := strings.Fields(name)
+			if len(fields) < 1 || strings.HasPrefix(fields[0], "#") {
+				continue
+			}
+
+			name = fields[0]
 		}
+
+		op, ok := pushswap.ParseOperation(name)
+		if !ok {
+			return nil, &commandError{index: len(instructions), line: line, command: name}
+		}
+
+		instructions = append(instructions, op)
 	}
 
 	err := inputScanner.Err()
@@ -118,53 +135,308 @@ func readInstructions(file string) ([]pushswap.Operation, error) {
 	return instructions, nil
 }
 
-func checkStacks(ds pushswap.DoubleStack[float64], reference []float64) (string, error) {
-	if ds.B.Len() > 0 || ds.A.Len() != len(reference) {
-		return "", fmt.Errorf("Got:\n%v\nExpected:\n%v", ds, reference)
+func checkStacks[T cmp.Ordered](ds pushswap.DoubleStack[T], reference []T) (string, error) {
+	if !ds.Holds(reference) {
+		return "KO", fmt.Errorf("Got:\n%v\nExpected:\n%v", ds, reference)
 	}
 
-	for i, val := range ds.A.All() {
-		if val != reference[i] {
-			return "KO", fmt.Errorf("Got:\n%v\nExpected:\n%v", ds, reference)
+	return "OK", nil
+}
+
+// loadPair reads the numbers and instructions of a file pair. A pair without
+// a numbers file takes its numbers from the command line arguments.
+func loadPair[T cmp.Ordered](pair filePair, args []string, parse cli.Parser[T], strict bool) ([]T, []pushswap.Operation, error) {
+	var numbers []T
+	var err error
+
+	if pair.numbersFile == "" {
+		numbers, err = parse.ParseArgs(args)
+	} else {
+		numbers, err = parse.ReadFile(pair.numbersFile)
+	}
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	instructions, err := readInstructions(pair.instructionsFile, strict)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return numbers, instructions, nil
+}
+
+// inputPairs returns the file pairs to check, or a single pair reading the
+// instructions from stdin when no files were given.
+func inputPairs(files filePairs) filePairs {
+	if len(files) < 1 {
+		return filePairs{{instructionsFile: "-"}}
+	}
+
+	return files
+}
+
+// checkPairs calls check on every pair, up to `jobs` pairs at a time, and
+// passes the results to emit in the order of the pairs. While several pairs
+// are checked at once, the trace of each pair is buffered and written just
+// before its result is emitted.
+func checkPairs[R any](pairs filePairs, jobs int, tr *tracer, check func(filePair, *tracer) R, emit func(R)) {
+	type traced struct {
+		result R
+		trace  *bytes.Buffer
+	}
+
+	buffered := tr != nil && batch.Jobs(jobs) > 1 && len(pairs) > 1
+
+	batch.Run(pairs, jobs, func(pair filePair) traced {
+		if !buffered {
+			return traced{result: check(pair, tr)}
+		}
+
+		var trace bytes.Buffer
+		return traced{result: check(pair, &tracer{output: &trace, stopOnInvalid: tr.stopOnInvalid}), trace: &trace}
+	}, func(_ int, t traced) {
+		if t.trace != nil {
+			tr.output.Write(t.trace.Bytes())
+		}
+
+		emit(t.result)
+	})
+}
+
+// verdict executes the instructions on the numbers and reports whether they
+// end up sorted.
+func verdict[T cmp.Ordered](numbers []T, instructions []pushswap.Operation, tr *tracer) string {
+	ds := pushswap.NewDoubleStack(numbers...)
+
+	execute(ds, instructions, tr)
+	status, _ := checkStacks(*ds, slices.Sorted(slices.Values(numbers)))
+	return status
+}
+
+// runCompat behaves like the original C checker: the verdict is printed to
+// stdout, unreadable input prints "Error" to stderr, and the returned exit
+// code is the worst outcome over all inputs.
+func runCompat[T cmp.Ordered](files filePairs, args []string, parse cli.Parser[T], tr *tracer, jobs int) int {
+	if len(files) < 1 && len(args) < 1 {
+		return exitOK
+	}
+
+	code := exitOK
+	checkPairs(inputPairs(files), jobs, tr, func(pair filePair, tr *tracer) string {
+		numbers, instructions, err := loadPair(pair, args, parse, true)
+		if err != nil {
+			return "Error"
 		}
+
+		tr.begin(pair)
+		return verdict(numbers, instructions, tr)
+	}, func(status string) {
+		code = max(code, printVerdict(status))
+	})
+
+	return code
+}
+
+// printVerdict prints "OK", "KO" or "Error" and returns the matching exit
+// code. Errors are printed to stderr.
+func printVerdict(status string) int {
+	switch status {
+	case "OK":
+		fmt.Println(status)
+		return exitOK
+	case "KO":
+		fmt.Println(status)
+		return exitKO
 	}
 
-	return "OK", nil
+	fmt.Fprintln(os.Stderr, status)
+	return exitError
 }
 
+// checked is the outcome of checkFiles.
+type checked struct {
+	status string
+	err    error
+}
+
+// checkFiles reads a file pair and executes the instructions on the numbers.
+func checkFiles[T cmp.Ordered](pair filePair, parse cli.Parser[T], tr *tracer) checked {
+	numbers, err := parse.ReadFile(pair.numbersFile)
+	if err != nil {
+		return checked{err: err}
+	}
+
+	ds := pushswap.NewDoubleStack(numbers...)
+	instructions, err := readInstructions(pair.instructionsFile, false)
+	if err != nil {
+		return checked{err: err}
+	}
+
+	tr.begin(pair)
+	execute(ds, instructions, tr)
+	sorted := make([]T, len(numbers))
+
+	copy(sorted, numbers)
+	slices.Sort(sorted)
+	status, err := checkStacks(*ds, sorted)
+	return checked{status: status, err: err}
+}
+
+// outputMode selects how the results are reported.
+type outputMode int
+
+const (
+	modeDefault outputMode = iota // OK or KO on stdout, errors logged.
+	modeCompat                    // Like the original checker, see runCompat.
+	modeJSON                      // A JSON report per input, see runJSON.
+	modeGrade                     // Graded results, see runGrade.
+)
+
 func main() {
-	allowDups := flag.Bool("allow-duplicates", false, "allow duplicate values in the number list")
+	var numbers cli.NumberFlags
+	numbers.Register(flag.CommandLine)
+	compat := flag.Bool("compat", false, "print only OK, KO or Error like the original checker and exit with the codes below, accepting only 32-bit integers unless -number-mode is set")
+	jsonOutput := flag.Bool("json", false, "print a JSON report per input on stdout instead of OK or KO")
+	grade := flag.Bool("grade", false, "print the instruction count and score of every input after OK or KO")
+	thresholdsFile := flag.String("thresholds-file", "", "read the grading thresholds from a file with one size:limit,... entry per line")
+	trace := flag.Bool("trace", false, "print the contents of both stacks to stderr after every instruction")
+	traceFile := flag.String("trace-file", "", "write the trace to this file instead of stderr, implies -trace")
+	traceStop := flag.Bool("trace-stop", false, "stop at the first instruction that cannot be applied, implies -trace")
+	jobs := flag.Int("jobs", 1, "check up to this many file pairs at a time, 0 for one per CPU")
+	manifest := flag.String("manifest", "", "check every *"+numbersExt+" file in this directory against the *"+instructionsExt+" file of the same name")
+	opsDir := flag.String("ops-dir", "", "read the *"+instructionsExt+" files for -manifest from this directory instead")
 	var files filePairs
 
+	var thresholds thresholdTable
+
 	flag.Var(&files, "files", "specifies an instructions file and a numbers file separated by a comma.")
+	flag.Var(&thresholds, "thresholds", "specifies the grading limits for a size as size:limit,limit,...")
 	flag.Usage = printHelp
-	flag.Parse()
-	args := flag.Args()
+	args, err := cli.ParseFlags(flag.CommandLine, os.Args[1:])
+	if err != nil {
+		log.Fatalln("ERROR:", err)
+	}
 
-	if len(files) < 1 {
-		if len(args) < 1 {
-			printHelp()
-			os.Exit(1)
+	if err := numbers.Check(flag.CommandLine); err != nil {
+		log.Fatalln("ERROR:", err)
+	}
+
+	// The original checker only accepts 32-bit integers.
+	if *compat && !cli.IsSet(flag.CommandLine, "number-mode") {
+		numbers.Mode = pushswap.ModeInt32
+	}
+
+	if *opsDir != "" && *manifest == "" {
+		log.Fatalln("ERROR: -ops-dir requires -manifest")
+	}
+
+	if *manifest != "" {
+		if *opsDir == "" {
+			*opsDir = *manifest
+		}
+
+		pairs, err := manifestPairs(*manifest, *opsDir)
+		if err != nil {
+			log.Fatalln("ERROR:", err)
+		}
+
+		files = append(files, pairs...)
+	}
+
+	var tr *tracer
+	if *trace || *traceFile != "" || *traceStop {
+		tr = &tracer{output: os.Stderr, stopOnInvalid: *traceStop}
+
+		if *traceFile != "" {
+			f, err := os.Create(*traceFile)
+			if err != nil {
+				log.Fatalln("ERROR:", fmt.Errorf("opening trace file: %v", err))
+			}
+
+			defer f.Close()
+			tr.output = f
 		}
+	}
+
+	var table thresholdTable
+	if *grade && !*compat {
+		table = slices.Clone(defaultThresholds)
+		if *thresholdsFile != "" {
+			var err error
 
-		var numStrings []string
-		for _, a := range args {
-			numStrings = append(numStrings, strings.Fields(a)...)
+			table, err = readThresholds(*thresholdsFile)
+			if err != nil {
+				log.Fatalln("ERROR:", err)
+			}
 		}
 
-		numbers, err := pushswap.ParseNumberSlice(numStrings, *allowDups)
+		for _, th := range thresholds {
+			table.put(th)
+		}
+	}
+
+	mode := modeDefault
+	switch {
+	case *compat:
+		mode = modeCompat
+	case *jsonOutput:
+		mode = modeJSON
+	case *grade:
+		mode = modeGrade
+	}
+
+	switch numbers.Type {
+	case "int":
+		os.Exit(run(mode, files, args, cli.TokenParser(&numbers, pushswap.ParseInt), tr, table, *jobs))
+	case "bigint":
+		os.Exit(run(mode, files, args, cli.TokenParser(&numbers, pushswap.ParseBigInt), tr, table, *jobs))
+	case "string":
+		os.Exit(run(mode, files, args, cli.TokenParser(&numbers, pushswap.ParseString), tr, table, *jobs))
+	case "version":
+		os.Exit(run(mode, files, args, cli.TokenParser(&numbers, pushswap.ParseVersion), tr, table, *jobs))
+	}
+
+	os.Exit(run(mode, files, args, cli.FloatParser(&numbers), tr, table, *jobs))
+}
+
+// run checks the inputs in the output mode and returns the exit code.
+func run[T cmp.Ordered](mode outputMode, files filePairs, args []string, parse cli.Parser[T], tr *tracer, table thresholdTable, jobs int) int {
+	// Without numbers or files, only the original checker does nothing
+	// instead of printing the usage.
+	if mode != modeCompat && len(files) < 1 && len(args) < 1 {
+		printHelp()
+		if mode == modeDefault {
+			return 1
+		}
+
+		return exitError
+	}
+
+	switch mode {
+	case modeCompat:
+		return runCompat(files, args, parse, tr, jobs)
+	case modeJSON:
+		return runJSON(files, args, parse, tr, table, jobs)
+	case modeGrade:
+		return runGrade(files, args, parse, tr, table, jobs)
+	}
+
+	if len(files) < 1 {
+		numbers, err := parse.ParseArgs(args)
 		if err != nil {
 			log.Fatalln("ERROR:", err)
 		}
 
 		ds := pushswap.NewDoubleStack(numbers...)
-		instructions, err := readInstructions("-")
+		instructions, err := readInstructions("-", false)
 		if err != nil {
 			log.Fatalln("ERROR:", err)
 		}
 
-		ds.ExecuteInstructions(instructions)
-		sorted := make([]float64, len(numbers))
+		execute(ds, instructions, tr)
+		sorted := make([]T, len(numbers))
 
 		copy(sorted, numbers)
 		slices.Sort(sorted)
@@ -175,32 +447,17 @@ func main() {
 
 		fmt.Println(status)
 	} else {
-		for _, pair := range files {
-			numbers, err := readNumbers(pair.numbersFile, *allowDups)
-			if err != nil {
-				log.Println("ERROR:", err)
-				continue
+		checkPairs(files, jobs, tr, func(pair filePair, tr *tracer) checked {
+			return checkFiles(pair, parse, tr)
+		}, func(c checked) {
+			if c.err != nil {
+				log.Println("ERROR:", c.err)
+				return
 			}
 
-			ds := pushswap.NewDoubleStack(numbers...)
-			instructions, err := readInstructions(pair.instructionsFile)
-			if err != nil {
-				log.Println("ERROR:", err)
-				continue
-			}
-
-			ds.ExecuteInstructions(instructions)
-			sorted := make([]float64, len(numbers))
-
-			copy(sorted, numbers)
-			slices.Sort(sorted)
-			status, err := checkStacks(*ds, sorted)
-			if err != nil {
-				log.Println("ERROR:", err)
-				continue
-			}
-
-			fmt.Println(status)
-		}
+			fmt.Println(c.status)
+		})
 	}
+
+	return 0
 }
@@ -0,0 +1,173 @@
+package main
+
+import (
+	"bufio"
+	"cmp"
+	"fmt"
+	"log"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"push-swap-go/internal/cli"
+)
+
+// threshold holds the operation limits for grading inputs of a given size.
+// A result scores one point for every limit its operation count is below.
+type threshold struct {
+	size   int
+	limits []int
+}
+
+// thresholdTable is a list of thresholds sorted by size.
+type thresholdTable []threshold
+
+// defaultThresholds are the limits used by the 42 push_swap evaluation.
+var defaultThresholds = thresholdTable{
+	{size: 3, limits: []int{4}},
+	{size: 5, limits: []int{13}},
+	{size: 100, limits: []int{700, 900, 1100, 1300, 1500}},
+	{size: 500, limits: []int{5500, 7000, 8500, 10000, 11500}},
+}
+
+// String is required by the flag.Value interface.
+func (t *thresholdTable) String() string {
+	var entries []string
+
+	for _, th := range *t {
+		limits := make([]string, len(th.limits))
+		for i, limit := range th.limits {
+			limits[i] = strconv.Itoa(limit)
+		}
+
+		entries = append(entries, fmt.Sprintf("%d:%s", th.size, strings.Join(limits, ",")))
+	}
+
+	return strings.Join(entries, " ")
+}
+
+// custom parsing logic for `thresholdTable`, entries look like `size:limit,limit,...`.
+func (t *thresholdTable) Set(value string) error {
+	sizeStr, limitsStr, found := strings.Cut(value, ":")
+	if !found {
+		return fmt.Errorf("usage: size:limit,limit,...")
+	}
+
+	size, err := strconv.Atoi(strings.TrimSpace(sizeStr))
+	if err != nil || size < 0 {
+		return fmt.Errorf("invalid size %q", sizeStr)
+	}
+
+	th := threshold{size: size}
+	for _, limitStr := range strings.Split(limitsStr, ",") {
+		limit, err := strconv.Atoi(strings.TrimSpace(limitStr))
+		if err != nil {
+			return fmt.Errorf("invalid limit %q", limitStr)
+		}
+
+		th.limits = append(th.limits, limit)
+	}
+
+	t.put(th)
+	return nil
+}
+
+// put adds a threshold to the table, replacing any threshold of the same size.
+func (t *thresholdTable) put(th threshold) {
+	*t = slices.DeleteFunc(*t, func(other threshold) bool { return other.size == th.size })
+	*t = append(*t, th)
+	slices.SortFunc(*t, func(a, b threshold) int { return a.size - b.size })
+}
+
+// readThresholds reads a threshold table from a file with one `size:limit,...`
+// entry per line. Blank lines and lines starting with '#' are ignored.
+func readThresholds(file string) (thresholdTable, error) {
+	input, err := os.Open(file)
+	if err != nil {
+		return nil, fmt.Errorf("opening file: %v", err)
+	}
+	defer input.Close()
+
+	var table thresholdTable
+	inputScanner := bufio.NewScanner(input)
+
+	for lineNo := 1; inputScanner.Scan(); lineNo++ {
+		line := strings.TrimSpace(inputScanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		err := table.Set(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %v", lineNo, err)
+		}
+	}
+
+	err = inputScanner.Err()
+	if err != nil {
+		return nil, fmt.Errorf("reading file: %v", err)
+	}
+
+	return table, nil
+}
+
+// grade scores the operation count for an input of the given size against the
+// smallest threshold covering that size. ok is false if no threshold does.
+func (t thresholdTable) grade(size, count int) (score, maxScore int, ok bool) {
+	idx := slices.IndexFunc(t, func(th threshold) bool { return th.size >= size })
+	if idx < 0 {
+		return 0, 0, false
+	}
+
+	for _, limit := range t[idx].limits {
+		if count < limit {
+			score++
+		}
+	}
+
+	return score, len(t[idx].limits), true
+}
+
+// gradeReport adds the score to a report. KO and Error results score nothing.
+func gradeReport[T cmp.Ordered](t thresholdTable, rep *report[T], size int) {
+	score, maxScore, ok := t.grade(size, rep.Count)
+	if !ok {
+		return
+	}
+
+	if rep.Verdict != "OK" {
+		score = 0
+	}
+
+	rep.Score = &score
+	rep.MaxScore = maxScore
+}
+
+// runGrade prints the verdict, operation count and score of every input and
+// returns the exit code for the worst outcome over all inputs.
+func runGrade[T cmp.Ordered](files filePairs, args []string, parse cli.Parser[T], tr *tracer, table thresholdTable, jobs int) int {
+	code := exitOK
+
+	checkPairs(inputPairs(files), jobs, tr, func(pair filePair, tr *tracer) report[T] {
+		return checkReport(pair, args, parse, tr, table)
+	}, func(rep report[T]) {
+		switch rep.Verdict {
+		case "OK":
+		case "KO":
+			code = max(code, exitKO)
+		default:
+			log.Println("ERROR:", rep.Error)
+			code = exitError
+			return
+		}
+
+		if rep.Score == nil {
+			fmt.Printf("%s %d -\n", rep.Verdict, rep.Count)
+		} else {
+			fmt.Printf("%s %d %d/%d\n", rep.Verdict, rep.Count, *rep.Score, rep.MaxScore)
+		}
+	})
+
+	return code
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// Extensions of the files paired by -manifest, as written by
+// `push-swap -input-dir`.
+const (
+	numbersExt      = ".nums"
+	instructionsExt = ".ops"
+)
+
+// basenames returns the names of the regular files in dir with the given
+// extension, without it.
+func basenames(dir, ext string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("reading directory: %v", err)
+	}
+
+	var names []string
+	for _, entry := range entries {
+		if entry.Type().IsRegular() && filepath.Ext(entry.Name()) == ext {
+			names = append(names, strings.TrimSuffix(entry.Name(), ext))
+		}
+	}
+
+	return names, nil
+}
+
+// manifestPairs pairs every numbersExt file in dir with the instructionsExt
+// file of the same basename in opsDir. A file without a counterpart is still
+// paired, so that the missing file is reported when the pair is checked.
+func manifestPairs(dir, opsDir string) (filePairs, error) {
+	numbers, err := basenames(dir, numbersExt)
+	if err != nil {
+		return nil, err
+	}
+
+	instructions, err := basenames(opsDir, instructionsExt)
+	if err != nil {
+		return nil, err
+	}
+
+	names := append(numbers, instructions...)
+	slices.Sort(names)
+	names = slices.Compact(names)
+
+	if len(names) < 1 {
+		return nil, fmt.Errorf("no *%s or *%s files in %s", numbersExt, instructionsExt, dir)
+	}
+
+	pairs := make(filePairs, len(names))
+	for i, name := range names {
+		pairs[i] = filePair{
+			instructionsFile: filepath.Join(opsDir, name+instructionsExt),
+			numbersFile:      filepath.Join(dir, name+numbersExt),
+		}
+	}
+
+	return pairs, nil
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"log"
+	"os"
+	"slices"
+	"time"
+
+	"push-swap-go/internal/cli"
+	"push-swap-go/internal/pushswap"
+)
+
+// report is the JSON record written for every checked file pair.
+type report[T cmp.Ordered] struct {
+	InstructionsFile string `json:"instructions_file"`
+	NumbersFile      string `json:"numbers_file,omitempty"`
+	Verdict          string `json:"verdict"` // OK, KO or Error
+	Error            string `json:"error,omitempty"`
+	// ErrorIndex and ErrorToken locate the number that was not accepted,
+	// and DuplicateOf the index of the number it equals if it is a
+	// duplicate.
+	ErrorIndex  *int                       `json:"error_index,omitempty"`
+	ErrorToken  string                     `json:"error_token,omitempty"`
+	DuplicateOf *int                       `json:"duplicate_of,omitempty"`
+	Count       int                        `json:"count"`
+	OpCounts    map[pushswap.Operation]int `json:"op_counts"`
+	// FirstInvalid is the 0-based index of the first instruction that
+	// was not recognised or could not be applied, or -1 if all of them
+	// were.
+	FirstInvalid int `json:"first_invalid"`
+	// FinalA and FinalB hold the stacks from top to bottom when the
+	// verdict is KO, and are omitted when empty.
+	FinalA    pushswap.JSONValues[T] `json:"final_a,omitempty"`
+	FinalB    pushswap.JSONValues[T] `json:"final_b,omitempty"`
+	ElapsedNS int64                  `json:"elapsed_ns"`
+	// Score and MaxScore are only set when grading.
+	Score    *int `json:"score,omitempty"`
+	MaxScore int  `json:"max_score,omitempty"`
+}
+
+// stackValues returns the values of a stack from top to bottom.
+func stackValues[T cmp.Ordered](s interface {
+	Len() int
+	Index(int) (T, bool)
+}) []T {
+	values := make([]T, s.Len())
+	for i := range values {
+		values[i], _ = s.Index(i)
+	}
+
+	return values
+}
+
+// setError describes the error, locating the number it refers to if a
+// number was not accepted, or the instruction if one was not recognised.
+// The message is the one without the highlighted tokens for numbers.
+func (rep *report[T]) setError(err error) {
+	var parseErr *pushswap.ParseError
+	var dupErr *pushswap.DuplicateError
+	var cmdErr *commandError
+
+	switch {
+	case errors.As(err, &parseErr):
+		rep.Error = parseErr.Error()
+		rep.ErrorIndex = &parseErr.Index
+		rep.ErrorToken = parseErr.Token
+	case errors.As(err, &dupErr):
+		rep.Error = dupErr.Error()
+		rep.ErrorIndex = &dupErr.SecondIndex
+		rep.ErrorToken = dupErr.Token
+		rep.DuplicateOf = &dupErr.FirstIndex
+	case errors.As(err, &cmdErr):
+		rep.Error = cmdErr.Error()
+		rep.FirstInvalid = cmdErr.index
+	default:
+		rep.Error = err.Error()
+	}
+}
+
+// checkReport checks a file pair and describes the outcome. The result is
+// graded when table is not nil.
+func checkReport[T cmp.Ordered](pair filePair, args []string, parse cli.Parser[T], tr *tracer, table thresholdTable) report[T] {
+	start := time.Now()
+	rep := report[T]{
+		InstructionsFile: pair.instructionsFile,
+		NumbersFile:      pair.numbersFile,
+		OpCounts:         map[pushswap.Operation]int{},
+		FirstInvalid:     -1,
+	}
+
+	numbers, instructions, err := loadPair(pair, args, parse, false)
+	if err != nil {
+		rep.Verdict = "Error"
+		rep.setError(err)
+		rep.ElapsedNS = time.Since(start).Nanoseconds()
+		return rep
+	}
+
+	ds := pushswap.NewDoubleStack(numbers...)
+
+	tr.begin(pair)
+	rep.FirstInvalid = execute(ds, instructions, tr)
+	rep.Verdict, _ = checkStacks(*ds, slices.Sorted(slices.Values(numbers)))
+	rep.Count = len(instructions)
+	for _, op := range instructions {
+		rep.OpCounts[op]++
+	}
+
+	if rep.Verdict != "OK" {
+		rep.FinalA = stackValues[T](&ds.A)
+		rep.FinalB = stackValues[T](&ds.B)
+	}
+
+	if table != nil {
+		gradeReport(table, &rep, len(numbers))
+	}
+
+	rep.ElapsedNS = time.Since(start).Nanoseconds()
+	return rep
+}
+
+// runJSON writes one JSON report per line to stdout and returns the exit
+// code for the worst outcome over all inputs.
+func runJSON[T cmp.Ordered](files filePairs, args []string, parse cli.Parser[T], tr *tracer, table thresholdTable, jobs int) int {
+	code := exitOK
+	encoder := json.NewEncoder(os.Stdout)
+
+	checkPairs(inputPairs(files), jobs, tr, func(pair filePair, tr *tracer) report[T] {
+		return checkReport(pair, args, parse, tr, table)
+	}, func(rep report[T]) {
+		switch rep.Verdict {
+		case "KO":
+			code = max(code, exitKO)
+		case "Error":
+			code = exitError
+		}
+
+		err := encoder.Encode(rep)
+		if err != nil {
+			log.Println("ERROR:", err)
+			code = exitError
+		}
+	})
+
+	return code
+}
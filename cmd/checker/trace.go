@@ -0,0 +1,66 @@
+package main
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+
+	"push-swap-go/internal/pushswap"
+)
+
+// tracer prints the contents of both stacks after every instruction.
+type tracer struct {
+	output io.Writer
+	// stopOnInvalid halts execution at the first instruction that
+	// could not be applied, e.g. pushing from an empty stack.
+	stopOnInvalid bool
+}
+
+// begin prints a header naming the file pair about to be traced. Numbers
+// given on the command line need no header.
+func (t *tracer) begin(pair filePair) {
+	if t == nil || pair.numbersFile == "" {
+		return
+	}
+
+	fmt.Fprintf(t.output, "== %s,%s\n", pair.instructionsFile, pair.numbersFile)
+}
+
+func printStep[T cmp.Ordered](t *tracer, step int, op pushswap.Operation, ds *pushswap.DoubleStack[T]) {
+	fmt.Fprintf(t.output, "[%d] %s\n", step, op)
+	fmt.Fprintln(t.output, "\tA:", &ds.A)
+	fmt.Fprintln(t.output, "\tB:", &ds.B)
+}
+
+// execute runs the instructions on the stacks, tracing each step when t is
+// not nil. It returns the index of the first instruction that could not be
+// applied, or -1 if all of them were.
+func execute[T cmp.Ordered](ds *pushswap.DoubleStack[T], instructions []pushswap.Operation, t *tracer) (firstInvalid int) {
+	firstInvalid = -1
+	if t != nil {
+		printStep(t, 0, "start", ds)
+	}
+
+	for i, op := range instructions {
+		applied := ds.ExecuteInstruction(op)
+		if applied == pushswap.Invalid && firstInvalid < 0 {
+			firstInvalid = i
+		}
+
+		if t == nil {
+			continue
+		}
+
+		printStep(t, i+1, op, ds)
+		if applied == pushswap.Invalid {
+			fmt.Fprintf(t.output, "instruction %d (%s) could not be applied\n", i+1, op)
+
+			if t.stopOnInvalid {
+				fmt.Fprintln(t.output, "stopped")
+				return firstInvalid
+			}
+		}
+	}
+
+	return firstInvalid
+}
@@ -0,0 +1,226 @@
+package main
+
+import (
+	"encoding/binary"
+	"flag"
+	"fmt"
+	"hash/fnv"
+	"log"
+	"math"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"push-swap-go/internal/gen"
+	"push-swap-go/internal/pushswap"
+)
+
+type sizeList []int
+
+// String is required by the flag.Value interface.
+func (s *sizeList) String() string {
+	strs := make([]string, len(*s))
+	for i, size := range *s {
+		strs[i] = strconv.Itoa(size)
+	}
+
+	return strings.Join(strs, ",")
+}
+
+// custom parsing logic for `sizeList`, replaces the default sizes.
+func (s *sizeList) Set(value string) error {
+	var sizes sizeList
+
+	for _, part := range strings.Split(value, ",") {
+		size, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil || size < 0 {
+			return fmt.Errorf("invalid size %q", part)
+		}
+
+		sizes = append(sizes, size)
+	}
+
+	*s = sizes
+	return nil
+}
+
+// trial is the outcome of solving one generated input.
+type trial struct {
+	seed     int64
+	numbers  []int
+	count    int
+	ok       bool
+	duration time.Duration
+}
+
+// summary holds the statistics of all the trials of one input size.
+type summary struct {
+	size     int
+	trials   int
+	min      int
+	mean     float64
+	p95      int
+	max      int
+	failures int
+	meanTime time.Duration
+	worst    trial
+}
+
+func printHelp() {
+	fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [options]\n", os.Args[0])
+	fmt.Fprintf(flag.CommandLine.Output(), "\tSolves seeded random inputs of each size with the push-swap algorithm, verifies\n")
+	fmt.Fprintf(flag.CommandLine.Output(), "\tthe instructions and reports statistics on the instruction counts.\n")
+	fmt.Fprintln(flag.CommandLine.Output())
+	flag.PrintDefaults()
+}
+
+// runTrial generates an input from the seed, solves it and verifies the result.
+func runTrial(size, minVal, maxVal int, seed int64) (trial, error) {
+	numbers, err := gen.UniqueInts(size, minVal, maxVal, seed)
+	if err != nil {
+		return trial{}, err
+	}
+
+	start := time.Now()
+	instructions := pushswap.TurkAlgorithm(numbers)
+	duration := time.Since(start)
+
+	return trial{
+		seed:     seed,
+		numbers:  numbers,
+		count:    len(instructions),
+		ok:       pushswap.Verify(numbers, instructions),
+		duration: duration,
+	}, nil
+}
+
+// summarise computes the statistics of the trials of one size. The worst
+// trial is the first failure, or the one with the most instructions.
+func summarise(size int, trials []trial) summary {
+	sum := summary{size: size, trials: len(trials)}
+	if len(trials) == 0 {
+		return sum
+	}
+
+	counts := make([]int, len(trials))
+	var totalTime time.Duration
+	total := 0
+
+	sum.worst = trials[0]
+	for i, t := range trials {
+		counts[i] = t.count
+		total += t.count
+		totalTime += t.duration
+
+		if !t.ok {
+			if sum.worst.ok {
+				sum.worst = t
+			}
+
+			sum.failures++
+		} else if sum.worst.ok && t.count > sum.worst.count {
+			sum.worst = t
+		}
+	}
+
+	slices.Sort(counts)
+	sum.min = counts[0]
+	sum.max = counts[len(counts)-1]
+	sum.mean = float64(total) / float64(len(counts))
+	// Nearest-rank percentile.
+	sum.p95 = counts[int(math.Ceil(0.95*float64(len(counts))))-1]
+	sum.meanTime = totalTime / time.Duration(len(trials))
+	return sum
+}
+
+// dumpWorst writes the worst input of a size to `worst-<size>.txt` in dir.
+func dumpWorst(dir string, sum summary) error {
+	strs := make([]string, len(sum.worst.numbers))
+	for i, n := range sum.worst.numbers {
+		strs[i] = strconv.Itoa(n)
+	}
+
+	file := filepath.Join(dir, fmt.Sprintf("worst-%d.txt", sum.size))
+	err := os.WriteFile(file, []byte(strings.Join(strs, " ")+"\n"), 0644)
+	if err != nil {
+		return fmt.Errorf("writing to file: %v", err)
+	}
+
+	return nil
+}
+
+// sizeSeed derives the seed of the trial seeds of a size from the seed given
+// on the command line. Both are hashed together, so that different pairs do
+// not share a stream as they would when simply added.
+func sizeSeed(seed int64, size int) int64 {
+	h := fnv.New64a()
+	binary.Write(h, binary.LittleEndian, [2]int64{seed, int64(size)})
+
+	return int64(h.Sum64())
+}
+
+func main() {
+	sizes := sizeList{3, 5, 100, 500}
+	trials := flag.Int("trials", 100, "number of random inputs to solve per size")
+	seed := flag.Int64("seed", 1, "seed from which the seeds of every trial are derived")
+	minVal := flag.Int("min", math.MinInt32, "minimum value (inclusive) of the generated numbers")
+	maxVal := flag.Int("max", math.MaxInt32, "maximum value (inclusive) of the generated numbers")
+	dumpDir := flag.String("dump", "", "write the worst input of every size to this directory")
+
+	flag.Var(&sizes, "sizes", "comma separated list of input sizes (default 3,5,100,500)")
+	flag.Usage = printHelp
+	flag.Parse()
+
+	if *trials < 1 {
+		log.Fatalln("ERROR: trials must be at least 1")
+	}
+
+	if *dumpDir != "" {
+		err := os.MkdirAll(*dumpDir, 0755)
+		if err != nil {
+			log.Fatalln("ERROR:", fmt.Errorf("creating dump directory: %v", err))
+		}
+	}
+
+	output := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.AlignRight)
+	failed := false
+
+	fmt.Fprintln(output, "size\ttrials\tmin\tmean\tp95\tmax\tfailures\tmean time\tworst seed\t")
+	for _, size := range sizes {
+		results := make([]trial, 0, *trials)
+		// Every size gets its own stream of trial seeds so that its results
+		// do not depend on which other sizes are evaluated.
+		rng := rand.New(rand.NewSource(sizeSeed(*seed, size)))
+
+		for range *trials {
+			t, err := runTrial(size, *minVal, *maxVal, rng.Int63())
+			if err != nil {
+				log.Fatalln("ERROR:", err)
+			}
+
+			results = append(results, t)
+		}
+
+		sum := summarise(size, results)
+		failed = failed || sum.failures > 0
+		fmt.Fprintf(output, "%d\t%d\t%d\t%.1f\t%d\t%d\t%d\t%v\t%d\t\n",
+			sum.size, sum.trials, sum.min, sum.mean, sum.p95, sum.max, sum.failures, sum.meanTime, sum.worst.seed)
+
+		if *dumpDir != "" {
+			err := dumpWorst(*dumpDir, sum)
+			if err != nil {
+				log.Println("ERROR:", err)
+			}
+		}
+	}
+
+	output.Flush()
+	if failed {
+		os.Exit(1)
+	}
+}
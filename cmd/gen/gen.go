@@ -0,0 +1,157 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"math"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"push-swap-go/internal/gen"
+)
+
+func printHelp() {
+	fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [options] count\n", os.Args[0])
+	fmt.Fprintf(flag.CommandLine.Output(), "\tPrints count random numbers on one line, drawn from the chosen distribution.\n")
+	fmt.Fprintln(flag.CommandLine.Output())
+	fmt.Fprintf(flag.CommandLine.Output(), "Distributions:\n")
+	fmt.Fprintf(flag.CommandLine.Output(), "\tuniform\t\tintegers (or floats with -float) between -min and -max\n")
+	fmt.Fprintf(flag.CommandLine.Output(), "\tduplicates\tfloats where -dup-percent percent share one value\n")
+	fmt.Fprintf(flag.CommandLine.Output(), "\tnearly-sorted\t0 to count-1 with a random cluster of -cluster values at -position\n")
+	fmt.Fprintf(flag.CommandLine.Output(), "\ttiny\t\tfloats between 0.0001 and 0.0009\n")
+	fmt.Fprintf(flag.CommandLine.Output(), "\tmassive\t\tfloats beyond the 32-bit integer range\n")
+	fmt.Fprintln(flag.CommandLine.Output())
+	fmt.Fprintf(flag.CommandLine.Output(), "Options that only apply to other distributions than -dist are rejected.\n")
+	fmt.Fprintln(flag.CommandLine.Output())
+	flag.PrintDefaults()
+}
+
+// formatInts joins integers with single spaces.
+func formatInts(nums []int) string {
+	strs := make([]string, len(nums))
+	for i, n := range nums {
+		strs[i] = strconv.Itoa(n)
+	}
+
+	return strings.Join(strs, " ")
+}
+
+// formatFloats joins floats with single spaces using the shortest exact representation.
+func formatFloats(nums []float64) string {
+	strs := make([]string, len(nums))
+	for i, n := range nums {
+		strs[i] = strconv.FormatFloat(n, 'g', -1, 64)
+	}
+
+	return strings.Join(strs, " ")
+}
+
+// distributionFlags lists the distributions the flags specific to some of
+// them apply to.
+var distributionFlags = map[string][]string{
+	"min":              {"uniform"},
+	"max":              {"uniform"},
+	"float":            {"uniform"},
+	"allow-duplicates": {"uniform"},
+	"dup-percent":      {"duplicates"},
+	"cluster":          {"nearly-sorted"},
+	"position":         {"nearly-sorted"},
+}
+
+func main() {
+	dist := flag.String("dist", "uniform", "distribution of the numbers: uniform, duplicates, nearly-sorted, tiny or massive")
+	minVal := flag.Int("min", math.MinInt32, "minimum value (inclusive) for the uniform distribution")
+	maxVal := flag.Int("max", math.MaxInt32, "maximum value (inclusive) for the uniform distribution")
+	float := flag.Bool("float", false, "print floats instead of integers for the uniform distribution")
+	allowDups := flag.Bool("allow-duplicates", false, "allow duplicate integers for the uniform distribution")
+	dupPercent := flag.Int("dup-percent", 50, "percentage of numbers sharing one value for the duplicates distribution")
+	cluster := flag.Int("cluster", 10, "size of the unsorted cluster for the nearly-sorted distribution")
+	position := flag.String("position", string(gen.Middle), "position of the cluster for the nearly-sorted distribution: top, middle or bottom")
+	seed := flag.Int64("seed", 0, "seed for reproducible output (default: current time)")
+
+	flag.Usage = printHelp
+	flag.Parse()
+
+	if flag.NArg() != 1 {
+		printHelp()
+		os.Exit(1)
+	}
+
+	count, err := strconv.Atoi(flag.Arg(0))
+	if err != nil || count < 0 {
+		log.Fatalf("ERROR: invalid count %q", flag.Arg(0))
+	}
+
+	seedSet := false
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == "seed" {
+			seedSet = true
+		}
+
+		dists, ok := distributionFlags[f.Name]
+		if ok && !slices.Contains(dists, *dist) {
+			log.Fatalf("ERROR: -%s does not apply to the %s distribution", f.Name, *dist)
+		}
+	})
+
+	if !seedSet {
+		*seed = time.Now().UnixNano()
+	}
+
+	if *minVal > *maxVal {
+		log.Fatalln("ERROR: min must be less than or equal to max")
+	}
+
+	if *dupPercent < 0 || *dupPercent > 100 {
+		log.Fatalln("ERROR: -dup-percent must be between 0 and 100")
+	}
+
+	if *cluster < 0 {
+		log.Fatalln("ERROR: -cluster must not be negative")
+	}
+
+	var output string
+
+	switch *dist {
+	case "uniform":
+		switch {
+		case *float:
+			output = formatFloats(gen.RandomFloats(count, float64(*minVal), float64(*maxVal), *seed))
+		case *allowDups:
+			nums, err := gen.RandomInts(count, *minVal, *maxVal, *seed)
+			if err != nil {
+				log.Fatalln("ERROR:", err)
+			}
+
+			output = formatInts(nums)
+		default:
+			nums, err := gen.UniqueInts(count, *minVal, *maxVal, *seed)
+			if err != nil {
+				log.Fatalln("ERROR:", err)
+			}
+
+			output = formatInts(nums)
+		}
+	case "duplicates":
+		output = formatFloats(gen.FloatsWithDuplicates(count, *dupPercent, *seed))
+	case "nearly-sorted":
+		pos := gen.Position(*position)
+		if pos != gen.Top && pos != gen.Middle && pos != gen.Bottom {
+			log.Fatalf("ERROR: unknown position %q", *position)
+		}
+
+		output = formatFloats(gen.NearlySorted(count, *cluster, pos, *seed))
+	case "tiny":
+		output = formatFloats(gen.TinyFloats(count, *seed))
+	case "massive":
+		output = formatFloats(gen.MassiveFloats(count, *seed))
+	default:
+		log.Fatalf("ERROR: unknown distribution %q", *dist)
+	}
+
+	fmt.Println(output)
+}
@@ -0,0 +1,317 @@
+package main
+
+import (
+	"bufio"
+	"cmp"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"push-swap-go/internal/cli"
+	"push-swap-go/internal/pushswap"
+)
+
+const prompt = "> "
+
+func printHelp() {
+	fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [options] [numbers...]\n", os.Args[0])
+	fmt.Fprintf(flag.CommandLine.Output(), "\tSorts numbers by hand by typing push-swap instructions, one or more per line.\n")
+	fmt.Fprintln(flag.CommandLine.Output())
+	printCommands(flag.CommandLine.Output())
+	fmt.Fprintln(flag.CommandLine.Output())
+	flag.PrintDefaults()
+}
+
+func printCommands(w io.Writer) {
+	fmt.Fprintf(w, "Commands:\n")
+	fmt.Fprintf(w, "\tsa sb ss pa pb ra rb rr rra rrb rrr\texecute instructions\n")
+	fmt.Fprintf(w, "\tload numbers...\tstart over with new numbers\n")
+	fmt.Fprintf(w, "\tundo [n]\tundo the last n instructions (default 1)\n")
+	fmt.Fprintf(w, "\treset\tundo every instruction\n")
+	fmt.Fprintf(w, "\tsave file\twrite the executed instructions to a file\n")
+	fmt.Fprintf(w, "\thint\tshow the next move of the Turk algorithm\n")
+	fmt.Fprintf(w, "\thelp\tshow this list\n")
+	fmt.Fprintf(w, "\tquit\texit\n")
+}
+
+// session is the state of a game: the numbers loaded and the instructions
+// executed on them.
+type session[T cmp.Ordered] struct {
+	numbers []T
+	stacks  *pushswap.DoubleStack[T]
+	history []pushswap.Operation // Executed instructions that could be applied.
+	parse   cli.Parser[T]
+	output  io.Writer
+}
+
+func (s *session[T]) load(args []string) error {
+	numbers, err := s.parse.ParseArgs(args)
+	if err != nil {
+		return err
+	}
+
+	s.start(numbers)
+	return nil
+}
+
+// start begins a new game with the numbers.
+func (s *session[T]) start(numbers []T) {
+	s.numbers = numbers
+	s.reset()
+}
+
+func (s *session[T]) reset() {
+	s.stacks = pushswap.NewDoubleStack(s.numbers...)
+	s.history = nil
+}
+
+// execute applies the instructions in order. Instructions that cannot be
+// applied are reported and left out of the history.
+func (s *session[T]) execute(instructions []pushswap.Operation) {
+	for _, op := range instructions {
+		if s.stacks.ExecuteInstruction(op) == pushswap.Invalid {
+			fmt.Fprintf(s.output, "%s could not be applied\n", op)
+			continue
+		}
+
+		s.history = append(s.history, op)
+	}
+}
+
+func (s *session[T]) undo(n int) {
+	n = min(n, len(s.history))
+
+	for range n {
+		last := s.history[len(s.history)-1]
+		s.history = s.history[:len(s.history)-1]
+		s.stacks.ExecuteInstruction(last.Inverse())
+	}
+}
+
+// save writes the history in the format read by the checker.
+func (s *session[T]) save(file string) error {
+	var transcript strings.Builder
+	for _, op := range s.history {
+		fmt.Fprintln(&transcript, op)
+	}
+
+	err := os.WriteFile(file, []byte(transcript.String()), 0644)
+	if err != nil {
+		return fmt.Errorf("writing to file: %v", err)
+	}
+
+	return nil
+}
+
+func (s *session[T]) sorted() bool {
+	return s.stacks.Holds(slices.Sorted(slices.Values(s.numbers)))
+}
+
+// show prints both stacks side by side from top to bottom.
+func (s *session[T]) show() {
+	a := make([]string, s.stacks.A.Len())
+	for i, val := range s.stacks.A.All() {
+		a[i] = fmt.Sprint(val)
+	}
+
+	b := make([]string, s.stacks.B.Len())
+	for i, val := range s.stacks.B.All() {
+		b[i] = fmt.Sprint(val)
+	}
+
+	width := 1
+	for _, str := range a {
+		width = max(width, len(str))
+	}
+
+	fmt.Fprintf(s.output, "%-*s | %s\n", width, "A", "B")
+	for i := range max(len(a), len(b)) {
+		var valA, valB string
+		if i < len(a) {
+			valA = a[i]
+		}
+		if i < len(b) {
+			valB = b[i]
+		}
+
+		row := fmt.Sprintf("%-*s | %s", width, valA, valB)
+		fmt.Fprintln(s.output, strings.TrimRight(row, " "))
+	}
+
+	status := fmt.Sprintf("%d instructions", len(s.history))
+	if s.sorted() {
+		status += ", sorted"
+	}
+
+	fmt.Fprintln(s.output, status)
+}
+
+// run executes one line of input and reports whether to keep going.
+func (s *session[T]) run(line string) bool {
+	fields := strings.Fields(line)
+	if len(fields) < 1 {
+		return true
+	}
+
+	command, args := fields[0], fields[1:]
+
+	switch command {
+	case "quit", "exit":
+		return false
+	case "help":
+		printCommands(s.output)
+		return true
+	case "load":
+		err := s.load(args)
+		if err != nil {
+			fmt.Fprintln(s.output, "error:", err)
+			return true
+		}
+	case "reset":
+		s.reset()
+	case "undo":
+		n := 1
+		if len(args) > 0 {
+			var err error
+			n, err = strconv.Atoi(args[0])
+			if err != nil || n < 1 {
+				fmt.Fprintf(s.output, "error: invalid count %q\n", args[0])
+				return true
+			}
+		}
+
+		s.undo(n)
+	case "save":
+		if len(args) != 1 {
+			fmt.Fprintln(s.output, "error: save takes a file name")
+			return true
+		}
+
+		err := s.save(args[0])
+		if err != nil {
+			fmt.Fprintln(s.output, "error:", err)
+		} else {
+			fmt.Fprintf(s.output, "saved %d instructions to %s\n", len(s.history), args[0])
+		}
+
+		return true
+	case "hint":
+		hint := pushswap.Hint(s.stacks)
+		if hint == nil {
+			fmt.Fprintln(s.output, "nothing to do, the stacks are sorted")
+		} else {
+			fmt.Fprintln(s.output, "hint:", strings.Join(operationStrings(hint), " "))
+		}
+
+		return true
+	default:
+		// Nothing is executed unless every instruction on the line is valid.
+		var instructions []pushswap.Operation
+		for _, field := range fields {
+			op, ok := pushswap.ParseOperation(field)
+			if !ok {
+				fmt.Fprintf(s.output, "error: unknown command %q, type help for a list\n", field)
+				return true
+			}
+
+			instructions = append(instructions, op)
+		}
+
+		s.execute(instructions)
+	}
+
+	s.show()
+	return true
+}
+
+func operationStrings(ops []pushswap.Operation) []string {
+	strs := make([]string, len(ops))
+	for i, op := range ops {
+		strs[i] = string(op)
+	}
+
+	return strs
+}
+
+func main() {
+	var numbers cli.NumberFlags
+	numbers.Register(flag.CommandLine)
+	numbersFile := flag.String("numbers", "", "read the numbers from this file instead of the command line")
+
+	flag.Usage = printHelp
+	args, err := cli.ParseFlags(flag.CommandLine, os.Args[1:])
+	if err != nil {
+		log.Fatalln("ERROR:", err)
+	}
+
+	if err := numbers.Check(flag.CommandLine); err != nil {
+		log.Fatalln("ERROR:", err)
+	}
+
+	if *numbersFile != "" && len(args) > 0 {
+		log.Fatalln("ERROR: numbers on the command line cannot be combined with -numbers")
+	}
+
+	switch numbers.Type {
+	case "int":
+		play(cli.TokenParser(&numbers, pushswap.ParseInt), *numbersFile, args)
+	case "bigint":
+		play(cli.TokenParser(&numbers, pushswap.ParseBigInt), *numbersFile, args)
+	case "string":
+		play(cli.TokenParser(&numbers, pushswap.ParseString), *numbersFile, args)
+	case "version":
+		play(cli.TokenParser(&numbers, pushswap.ParseVersion), *numbersFile, args)
+	default:
+		play(cli.FloatParser(&numbers), *numbersFile, args)
+	}
+}
+
+// play loads the numbers of the file, or of the command line arguments when
+// file is empty, and runs the commands read from stdin on them.
+func play[T cmp.Ordered](parse cli.Parser[T], file string, args []string) {
+	s := &session[T]{
+		parse:  parse,
+		output: os.Stdout,
+	}
+
+	var numbers []T
+	var err error
+
+	if file == "" {
+		numbers, err = parse.ParseArgs(args)
+	} else {
+		numbers, err = parse.ReadFile(file)
+	}
+
+	if err != nil {
+		log.Fatalln("ERROR:", err)
+	}
+
+	s.start(numbers)
+	fmt.Fprintln(s.output, "Type help for a list of commands.")
+	s.show()
+
+	input := bufio.NewScanner(os.Stdin)
+	for {
+		fmt.Fprint(s.output, prompt)
+
+		if !input.Scan() {
+			fmt.Fprintln(s.output)
+			break
+		}
+
+		if !s.run(input.Text()) {
+			break
+		}
+	}
+
+	err = input.Err()
+	if err != nil {
+		log.Fatalln("ERROR:", fmt.Errorf("reading input: %v", err))
+	}
+}
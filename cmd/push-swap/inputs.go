@@ -0,0 +1,99 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Extensions of the files read and written when solving a directory. The
+// checker pairs files with these extensions by basename.
+const (
+	numbersExt      = ".nums"
+	instructionsExt = ".ops"
+)
+
+type patterns []string
+
+// String is required by the flag.Value interface.
+func (p *patterns) String() string {
+	return strings.Join(*p, " ")
+}
+
+// custom parsing logic for `patterns`.
+func (p *patterns) Set(value string) error {
+	_, err := filepath.Match(value, "")
+	if err != nil {
+		return fmt.Errorf("invalid pattern %q: %v", value, err)
+	}
+
+	*p = append(*p, value)
+	return nil
+}
+
+// outputName replaces the extension of an input file with instructionsExt.
+func outputName(input string) string {
+	base := filepath.Base(input)
+	return strings.TrimSuffix(base, filepath.Ext(base)) + instructionsExt
+}
+
+// expandInputs returns a file pair for every regular file matching one of
+// the glob patterns. Patterns are relative to inputDir when it is set, and
+// default to every numbersExt file in it. The instructions for an input are
+// written to outputDir, or next to the input when it is empty.
+func expandInputs(inputDir, outputDir string, globs patterns) (filePairs, error) {
+	if inputDir != "" && len(globs) < 1 {
+		globs = patterns{"*" + numbersExt}
+	}
+
+	var pairs filePairs
+	seen := map[string]bool{}
+	outputs := map[string]string{}
+
+	for _, pattern := range globs {
+		if inputDir != "" {
+			pattern = filepath.Join(inputDir, pattern)
+		}
+
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern %q: %v", pattern, err)
+		}
+
+		for _, input := range matches {
+			info, err := os.Stat(input)
+			if err != nil || !info.Mode().IsRegular() || seen[input] {
+				continue
+			}
+
+			seen[input] = true
+
+			dir := filepath.Dir(input)
+			if outputDir != "" {
+				dir = outputDir
+			}
+
+			output := filepath.Join(dir, outputName(input))
+			if other, ok := outputs[output]; ok {
+				return nil, fmt.Errorf("both %s and %s would be written to %s", other, input, output)
+			}
+
+			outputs[output] = input
+			pairs = append(pairs, filePair{Input: input, Output: output})
+		}
+	}
+
+	if len(pairs) < 1 {
+		return nil, fmt.Errorf("no input files match %s", globs.String())
+	}
+
+	if outputDir != "" {
+		err := os.MkdirAll(outputDir, 0755)
+		if err != nil {
+			return nil, fmt.Errorf("creating output directory: %v", err)
+		}
+	}
+
+	return pairs, nil
+}
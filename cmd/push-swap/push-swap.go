@@ -2,13 +2,20 @@ package main
 
 import (
 	"bufio"
+	"cmp"
+	"encoding/json"
 	"flag"
 	"fmt"
 	"io"
 	"log"
 	"os"
+	"slices"
 	"strings"
+	"text/tabwriter"
+	"time"
 
+	"push-swap-go/internal/batch"
+	"push-swap-go/internal/cli"
 	"push-swap-go/internal/pushswap"
 )
 
@@ -45,113 +52,325 @@ func (f *filePairs) Set(value string) error {
 }
 
 func printHelp() {
-	fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [options]", os.Args[0])
-	fmt.Fprintf(flag.CommandLine.Output(), "\tReads space separated numbers from stdin and writes the push-swap instructions")
-	fmt.Fprintf(flag.CommandLine.Output(), "\tfor sorting them to stdout by default.")
+	fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [options] [numbers...]\n", os.Args[0])
+	fmt.Fprintf(flag.CommandLine.Output(), "\tWrites the push-swap instructions for sorting the numbers given via the\n")
+	fmt.Fprintf(flag.CommandLine.Output(), "\tcommand line to stdout, or the space separated numbers read from stdin when\n")
+	fmt.Fprintf(flag.CommandLine.Output(), "\tthere are none. Invalid or duplicate numbers on the command line are reported\n")
+	fmt.Fprintf(flag.CommandLine.Output(), "\tas Error on stderr with exit code 1, like the original.\n")
 	fmt.Fprintln(flag.CommandLine.Output())
 	flag.PrintDefaults()
 }
 
-func readNumbers(file string, allowDups bool) ([]float64, error) {
-	input := os.Stdin
+func writeInstructions(file string, instructions []pushswap.Operation) (int, error) {
+	output := os.Stdout
 
 	if file != "-" {
-		f, err := os.Open(file)
+		f, err := os.Create(file)
 		if err != nil {
-			return nil, fmt.Errorf("opening file: %v", err)
+			return -1, fmt.Errorf("opening file: %v", err)
 		}
 
 		defer f.Close()
-		input = f
+		output = f
 	}
 
-	var numStrings []string
-	var line strings.Builder
-	inputReader := bufio.NewReader(input)
+	bytesWritten := 0
+	for _, op := range instructions {
+		n, err := fmt.Fprintln(output, op)
+		if err != nil {
+			return n, fmt.Errorf("writing to file: %v", err)
+		}
 
-	for {
-		buf, isPrefix, err := inputReader.ReadLine()
+		bytesWritten += n
+	}
 
-		if err != nil && err != io.EOF {
-			return nil, fmt.Errorf("reading file: %v", err)
+	return bytesWritten, nil
+}
+
+// explanation is the JSON output of -explain json.
+type explanation[T cmp.Ordered] struct {
+	Instructions []pushswap.Operation `json:"instructions"`
+	Steps        []pushswap.Step[T]   `json:"steps"`
+}
+
+// comment describes a step on a single line.
+func comment[T cmp.Ordered](step pushswap.Step[T]) string {
+	if step.Move == nil {
+		return fmt.Sprintf("# %s", step.Phase)
+	}
+
+	from, to := "A", "B"
+	if step.Phase == pushswap.PhasePushToA {
+		from, to = "B", "A"
+	}
+
+	move := step.Move
+	return fmt.Sprintf("# %s: %v from %s[%d] onto %s[%d], costs %s %d, %s %d, %s %d: %s",
+		step.Phase, move.Value, from, move.FromIndex, to, move.TargetIndex,
+		pushswap.RouteRotate, move.Costs.Rotate,
+		pushswap.RouteReverseRotate, move.Costs.ReverseRotate,
+		pushswap.RouteSeparate, move.Costs.Separate, move.Route)
+}
+
+// writeExplanation writes the instructions with the reasoning behind them,
+// either as comment lines before every step or as JSON.
+func writeExplanation[T cmp.Ordered](file, format string, instructions []pushswap.Operation, steps []pushswap.Step[T]) error {
+	output := os.Stdout
+
+	if file != "-" {
+		f, err := os.Create(file)
+		if err != nil {
+			return fmt.Errorf("opening file: %v", err)
 		}
 
-		// Process the line if it has content
-		if len(buf) > 0 {
-			line.WriteString(string(buf))
+		defer f.Close()
+		output = f
+	}
 
-			if !isPrefix {
-				numStrings = append(numStrings, strings.Fields(line.String())...)
-				line.Reset()
-			}
+	if format == "json" {
+		if instructions == nil {
+			instructions = []pushswap.Operation{}
+			steps = []pushswap.Step[T]{}
 		}
 
-		// Exit the loop on EOF
-		if err == io.EOF {
-			break
+		err := json.NewEncoder(output).Encode(explanation[T]{Instructions: instructions, Steps: steps})
+		if err != nil {
+			return fmt.Errorf("writing to file: %v", err)
+		}
+
+		return nil
+	}
+
+	writer := bufio.NewWriter(output)
+	for _, step := range steps {
+		fmt.Fprintln(writer, comment(step))
+
+		for _, op := range step.Instructions {
+			fmt.Fprintln(writer, op)
 		}
 	}
 
-	numbers, err := pushswap.ParseNumberSlice(numStrings, allowDups)
+	err := writer.Flush()
 	if err != nil {
-		return nil, err
+		return fmt.Errorf("writing to file: %v", err)
 	}
 
-	return numbers, nil
+	return nil
 }
 
-func writeInstructions(file string, instructions []pushswap.Operation) (int, error) {
+// writeResult writes the numbers and the instructions sorting them as a
+// single line of JSON, in the format of the /solve response of the server.
+func writeResult[T cmp.Ordered](file string, sol solution[T]) error {
 	output := os.Stdout
 
 	if file != "-" {
 		f, err := os.Create(file)
 		if err != nil {
-			return -1, fmt.Errorf("opening file: %v", err)
+			return fmt.Errorf("opening file: %v", err)
 		}
 
 		defer f.Close()
 		output = f
 	}
 
-	bytesWritten := 0
-	for _, op := range instructions {
-		n, err := fmt.Fprintln(output, op)
-		if err != nil {
-			return n, fmt.Errorf("writing to file: %v", err)
+	res := pushswap.NewResult(sol.numbers, "turk", sol.instructions, sol.duration)
+	err := json.NewEncoder(output).Encode(res)
+	if err != nil {
+		return fmt.Errorf("writing to file: %v", err)
+	}
+
+	return nil
+}
+
+// printStats writes the instructions spent per phase and a histogram of the
+// costs of the moves in both push phases.
+func printStats(w io.Writer, input string, stats pushswap.TurkStats) {
+	output := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	toB, toA := stats.Phase(pushswap.PhasePushToB), stats.Phase(pushswap.PhasePushToA)
+
+	fmt.Fprintf(output, "stats for %s\n", input)
+	fmt.Fprintln(output, "phase\tinstructions\tmoves\t")
+	for _, phase := range stats.Phases {
+		fmt.Fprintf(output, "%s\t%d\t%d\t\n", phase.Phase, phase.Instructions, phase.Moves)
+	}
+	fmt.Fprintf(output, "total\t%d\t%d\t\n", stats.Instructions, toB.Moves+toA.Moves)
+
+	// Only the costs of at least one move are listed.
+	costs := append(toB.Costs.Costs(), toA.Costs.Costs()...)
+	slices.Sort(costs)
+	costs = slices.Compact(costs)
+
+	if len(costs) > 0 {
+		fmt.Fprintln(output)
+		fmt.Fprintf(output, "move cost\t%s\t%s\t\n", toB.Phase, toA.Phase)
+		for _, cost := range costs {
+			fmt.Fprintf(output, "%d\t%d\t%d\t\n", cost, toB.Costs[cost], toA.Costs[cost])
 		}
+	}
 
-		bytesWritten += n
+	output.Flush()
+}
+
+// solution holds the instructions for sorting the numbers of an input file,
+// or the error reading it.
+type solution[T cmp.Ordered] struct {
+	numbers      []T
+	instructions []pushswap.Operation
+	steps        []pushswap.Step[T] // Only set when explaining.
+	duration     time.Duration      // Time the algorithm took.
+	err          error
+}
+
+// solve reads the numbers of an input file, or takes them from the command
+// line arguments when file is empty, and sorts them, recording the steps of
+// the algorithm when explain is set.
+func solve[T cmp.Ordered](file string, args []string, parse cli.Parser[T], explain bool) solution[T] {
+	var numbers []T
+	var err error
+
+	if file == "" {
+		numbers, err = parse.ParseArgs(args)
+	} else {
+		numbers, err = parse.ReadFile(file)
 	}
 
-	return bytesWritten, nil
+	if err != nil {
+		return solution[T]{err: err}
+	}
+
+	sol := solution[T]{numbers: numbers}
+	start := time.Now()
+
+	if explain {
+		sol.instructions, sol.steps = pushswap.ExplainTurkAlgorithm(numbers)
+	} else {
+		sol.instructions = pushswap.TurkAlgorithm(numbers)
+	}
+
+	sol.duration = time.Since(start)
+	return sol
+}
+
+// solveAll solves the file pairs, up to `jobs` at a time, and writes the
+// instructions in the output format, explanations and statistics in the order
+// of the pairs. It returns the exit code, which is 1 when the numbers on the
+// command line are invalid.
+func solveAll[T cmp.Ordered](files filePairs, args []string, parse cli.Parser[T], jobs int, outputFormat, explain string, showStats bool) int {
+	code := 0
+
+	batch.Run(files, jobs, func(pair filePair) solution[T] {
+		return solve(pair.Input, args, parse, explain != "" || showStats)
+	}, func(i int, sol solution[T]) {
+		pair := files[i]
+		if sol.err != nil {
+			// Like the original, invalid numbers on the command line are
+			// only reported as Error.
+			if pair.Input == "" {
+				fmt.Fprintln(os.Stderr, "Error")
+				code = 1
+			} else {
+				log.Println("ERROR:", sol.err)
+			}
+			return
+		}
+
+		var err error
+		if showStats {
+			input := pair.Input
+			if input == "" {
+				input = "arguments"
+			}
+
+			printStats(os.Stderr, input, pushswap.StepStats(sol.steps))
+		}
+
+		if explain != "" {
+			err = writeExplanation(pair.Output, explain, sol.instructions, sol.steps)
+		} else if outputFormat == "json" {
+			err = writeResult(pair.Output, sol)
+		} else {
+			_, err = writeInstructions(pair.Output, sol.instructions)
+		}
+
+		if err != nil {
+			log.Println("ERROR:", err)
+		}
+	})
+
+	return code
 }
 
 func main() {
-	allowDups := flag.Bool("allow-duplicates", false, "allow duplicate values in the number list")
+	var numbers cli.NumberFlags
+	numbers.Register(flag.CommandLine)
+	explain := flag.String("explain", "", "annotate the instructions with the reasoning behind every move: comments or json")
+	outputFormat := flag.String("output-format", "text", "write the instructions as text, one per line, or as json along with the input, count and duration, like the /solve response of pushswapd")
+	showStats := flag.Bool("stats", false, "print the instructions spent in every phase of the algorithm to stderr")
+	jobs := flag.Int("jobs", 1, "solve up to this many input files at a time, 0 for one per CPU")
+	inputDir := flag.String("input-dir", "", "solve the files matching -glob in this directory, every *"+numbersExt+" file by default")
+	outputDir := flag.String("output-dir", "", "write the instructions for -input-dir and -glob inputs to this directory instead of next to the input")
 	var files filePairs
+	var globs patterns
 
 	flag.Var(&files, "files", "specifies an input file and an optional output file separated by a comma.")
+	flag.Var(&globs, "glob", "solve every file matching a glob pattern, writing the instructions to a *"+instructionsExt+" file of the same name")
 	flag.Usage = printHelp
-	flag.Parse()
+	args, err := cli.ParseFlags(flag.CommandLine, os.Args[1:])
+	if err != nil {
+		log.Fatalln("ERROR:", err)
+	}
 
-	if len(files) < 1 {
-		files = append(files, filePair{Input: "-", Output: "-"})
+	if err := numbers.Check(flag.CommandLine); err != nil {
+		log.Fatalln("ERROR:", err)
+	}
+
+	if *explain != "" && *explain != "comments" && *explain != "json" {
+		log.Fatalf("ERROR: unknown -explain format %q, want comments or json\n", *explain)
+	}
+
+	if *outputFormat != "text" && *outputFormat != "json" {
+		log.Fatalf("ERROR: unknown -output-format %q, want text or json\n", *outputFormat)
+	}
+
+	if *outputFormat == "json" && *explain != "" {
+		log.Fatalln("ERROR: -output-format json cannot be combined with -explain")
 	}
 
-	for _, pair := range files {
+	if *outputDir != "" && *inputDir == "" && len(globs) < 1 {
+		log.Fatalln("ERROR: -output-dir requires -input-dir or -glob")
+	}
 
-		numbers, err := readNumbers(pair.Input, *allowDups)
+	if *inputDir != "" || len(globs) > 0 {
+		inputs, err := expandInputs(*inputDir, *outputDir, globs)
 		if err != nil {
-			log.Println("ERROR:", err)
-			continue
+			log.Fatalln("ERROR:", err)
 		}
 
-		instructions := pushswap.TurkAlgorithm(numbers)
+		files = append(files, inputs...)
+	}
 
-		_, err = writeInstructions(pair.Output, instructions)
-		if err != nil {
-			log.Println("ERROR:", err)
-			continue
+	if len(args) > 0 {
+		if len(files) > 0 {
+			log.Fatalln("ERROR: numbers on the command line cannot be combined with -files, -input-dir or -glob")
 		}
+
+		files = append(files, filePair{Output: "-"})
+	}
+
+	if len(files) < 1 {
+		files = append(files, filePair{Input: "-", Output: "-"})
+	}
+
+	switch numbers.Type {
+	case "int":
+		os.Exit(solveAll(files, args, cli.TokenParser(&numbers, pushswap.ParseInt), *jobs, *outputFormat, *explain, *showStats))
+	case "bigint":
+		os.Exit(solveAll(files, args, cli.TokenParser(&numbers, pushswap.ParseBigInt), *jobs, *outputFormat, *explain, *showStats))
+	case "string":
+		os.Exit(solveAll(files, args, cli.TokenParser(&numbers, pushswap.ParseString), *jobs, *outputFormat, *explain, *showStats))
+	case "version":
+		os.Exit(solveAll(files, args, cli.TokenParser(&numbers, pushswap.ParseVersion), *jobs, *outputFormat, *explain, *showStats))
+	default:
+		os.Exit(solveAll(files, args, cli.FloatParser(&numbers), *jobs, *outputFormat, *explain, *showStats))
 	}
 }
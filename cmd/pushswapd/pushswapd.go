@@ -0,0 +1,82 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"push-swap-go/internal/server"
+)
+
+func printHelp() {
+	fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [options]\n", os.Args[0])
+	fmt.Fprintf(flag.CommandLine.Output(), "\tServes a JSON API solving and checking push-swap instructions:\n")
+	fmt.Fprintln(flag.CommandLine.Output())
+	fmt.Fprintf(flag.CommandLine.Output(), "\tPOST /solve\t{\"numbers\": [...], \"algorithm\": \"turk\", \"options\": {\"allow_duplicates\": false}}\n")
+	fmt.Fprintf(flag.CommandLine.Output(), "\tPOST /check\t{\"numbers\": [...], \"instructions\": [\"pb\", ...], \"options\": {...}}\n")
+	fmt.Fprintln(flag.CommandLine.Output())
+	fmt.Fprintf(flag.CommandLine.Output(), "\tStops gracefully on SIGINT or SIGTERM.\n")
+	fmt.Fprintln(flag.CommandLine.Output())
+	flag.PrintDefaults()
+}
+
+func main() {
+	addr := flag.String("addr", "localhost:8080", "address to listen on")
+	maxBody := flag.Int64("max-body", server.DefaultConfig.MaxBodyBytes, "largest accepted request body in bytes")
+	maxNumbers := flag.Int("max-numbers", server.DefaultConfig.MaxNumbers, "largest accepted number list (0: no limit)")
+	timeout := flag.Duration("timeout", server.DefaultConfig.Timeout, "time after which a request is abandoned (0: no limit)")
+	shutdownTimeout := flag.Duration("shutdown-timeout", 5*time.Second, "time given to requests in progress when stopping")
+
+	flag.Usage = printHelp
+	flag.Parse()
+
+	srv := &http.Server{
+		Handler: server.New(server.Config{
+			MaxBodyBytes: *maxBody,
+			MaxNumbers:   *maxNumbers,
+			Timeout:      *timeout,
+		}),
+		ReadHeaderTimeout: 10 * time.Second,
+		ReadTimeout:       30 * time.Second,
+		IdleTimeout:       time.Minute,
+	}
+
+	listener, err := net.Listen("tcp", *addr)
+	if err != nil {
+		log.Fatalln("ERROR:", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- srv.Serve(listener)
+	}()
+
+	log.Println("listening on", listener.Addr())
+
+	select {
+	case err = <-serveErr:
+		log.Fatalln("ERROR:", err)
+	case <-ctx.Done():
+	}
+
+	log.Println("shutting down")
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), *shutdownTimeout)
+	defer cancel()
+
+	err = srv.Shutdown(shutdownCtx)
+	if err != nil && !errors.Is(err, http.ErrServerClosed) {
+		log.Fatalln("ERROR:", fmt.Errorf("shutting down: %v", err))
+	}
+}
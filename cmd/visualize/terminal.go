@@ -0,0 +1,126 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+type key int
+
+const (
+	keyOther key = iota
+	keyPlayPause
+	keyForward
+	keyBack
+	keyFaster
+	keySlower
+	keyQuit
+)
+
+// terminal reads single key presses from the controlling terminal.
+type terminal struct {
+	tty   *os.File
+	state string // Settings saved by `stty -g` to be restored on exit.
+}
+
+// stty runs the stty command on the terminal and returns its output.
+func stty(tty *os.File, args ...string) (string, error) {
+	cmd := exec.Command("stty", args...)
+	cmd.Stdin = tty
+
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("running stty: %v", err)
+	}
+
+	return strings.TrimSpace(string(out)), nil
+}
+
+// openTerminal switches the controlling terminal to unbuffered input without
+// echo. The previous settings are restored by close.
+func openTerminal() (*terminal, error) {
+	tty, err := os.OpenFile("/dev/tty", os.O_RDWR, 0)
+	if err != nil {
+		return nil, fmt.Errorf("opening terminal: %v", err)
+	}
+
+	state, err := stty(tty, "-g")
+	if err == nil {
+		_, err = stty(tty, "cbreak", "-echo")
+	}
+
+	if err != nil {
+		tty.Close()
+		return nil, err
+	}
+
+	return &terminal{tty: tty, state: state}, nil
+}
+
+func (t *terminal) close() {
+	stty(t.tty, t.state)
+	t.tty.Close()
+}
+
+// rows returns the height of the terminal, or 0 if it is unknown.
+func (t *terminal) rows() int {
+	out, err := stty(t.tty, "size")
+	if err != nil {
+		return 0
+	}
+
+	var rows, cols int
+	fmt.Sscan(out, &rows, &cols)
+	return rows
+}
+
+// keys sends every key press to the returned channel until reading fails.
+func (t *terminal) keys() <-chan key {
+	keys := make(chan key)
+
+	go func() {
+		defer close(keys)
+		input := bufio.NewReader(t.tty)
+
+		for {
+			b, err := input.ReadByte()
+			if err != nil {
+				return
+			}
+
+			switch b {
+			case ' ', 'p':
+				keys <- keyPlayPause
+			case 'l', 'n':
+				keys <- keyForward
+			case 'h', 'b':
+				keys <- keyBack
+			case '+', '=':
+				keys <- keyFaster
+			case '-', '_':
+				keys <- keySlower
+			case 'q':
+				keys <- keyQuit
+			case '\x1b': // Arrow keys are sent as ESC [ C and ESC [ D.
+				if next, _ := input.Peek(2); len(next) == 2 && next[0] == '[' {
+					direction := next[1]
+					input.Discard(2)
+
+					switch direction {
+					case 'C':
+						keys <- keyForward
+					case 'D':
+						keys <- keyBack
+					}
+				}
+			default:
+				keys <- keyOther
+			}
+		}
+	}()
+
+	return keys
+}
@@ -0,0 +1,417 @@
+package main
+
+import (
+	"bufio"
+	"cmp"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"push-swap-go/internal/cli"
+	"push-swap-go/internal/pushswap"
+	"push-swap-go/internal/render"
+)
+
+const (
+	minDelay = time.Millisecond
+	maxDelay = 2 * time.Second
+)
+
+func printHelp() {
+	fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [options] numbers...\n", os.Args[0])
+	fmt.Fprintf(flag.CommandLine.Output(), "\tAnimates the instructions read from stdin on the numbers given via the command line or -numbers.\n")
+	fmt.Fprintln(flag.CommandLine.Output())
+	fmt.Fprintf(flag.CommandLine.Output(), "Controls:\n")
+	fmt.Fprintf(flag.CommandLine.Output(), "\tspace\tplay or pause\n")
+	fmt.Fprintf(flag.CommandLine.Output(), "\tright, l\tstep forward\n")
+	fmt.Fprintf(flag.CommandLine.Output(), "\tleft, h\tstep back\n")
+	fmt.Fprintf(flag.CommandLine.Output(), "\t+, -\tspeed up or slow down\n")
+	fmt.Fprintf(flag.CommandLine.Output(), "\tq\tquit\n")
+	fmt.Fprintln(flag.CommandLine.Output())
+	fmt.Fprintf(flag.CommandLine.Output(), "With -html, -svg, -png or -gif the replay is exported instead of animated.\n")
+	fmt.Fprintln(flag.CommandLine.Output())
+	flag.PrintDefaults()
+}
+
+func readInstructions(file string) ([]pushswap.Operation, error) {
+	input := os.Stdin
+
+	if file != "-" {
+		f, err := os.Open(file)
+		if err != nil {
+			return nil, fmt.Errorf("opening file: %v", err)
+		}
+
+		defer f.Close()
+		input = f
+	}
+
+	var instructions []pushswap.Operation
+	inputScanner := bufio.NewScanner(input)
+
+	for inputScanner.Scan() {
+		fields := strings.Fields(inputScanner.Text())
+		if len(fields) < 1 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+
+		op, ok := pushswap.ParseOperation(fields[0])
+		if !ok {
+			return nil, fmt.Errorf("unrecognised command %q", fields[0])
+		}
+
+		instructions = append(instructions, op)
+	}
+
+	err := inputScanner.Err()
+	if err != nil {
+		return nil, fmt.Errorf("reading file: %v", err)
+	}
+
+	return instructions, nil
+}
+
+// readRanks reads the numbers of the file, or takes them from the command
+// line arguments when file is empty, and returns their ranks.
+func readRanks[T cmp.Ordered](parse cli.Parser[T], file string, args []string) ([]int, error) {
+	var numbers []T
+	var err error
+
+	if file == "" {
+		numbers, err = parse.ParseArgs(args)
+	} else {
+		numbers, err = parse.ReadFile(file)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return pushswap.Ranks(numbers), nil
+}
+
+// exportHTML writes the replay as a self-contained HTML page.
+func exportHTML(file string, ranks []int, instructions []pushswap.Operation) error {
+	f, err := os.Create(file)
+	if err != nil {
+		return fmt.Errorf("creating file: %v", err)
+	}
+
+	defer f.Close()
+	return render.WriteHTML(f, ranks, instructions)
+}
+
+// exportFrames writes every frame of the replay to `frame-<step>.<ext>` in dir.
+func exportFrames(dir, ext string, ranks []int, instructions []pushswap.Operation, write func(io.Writer, render.Frame) error) error {
+	err := os.MkdirAll(dir, 0755)
+	if err != nil {
+		return fmt.Errorf("creating directory: %v", err)
+	}
+
+	for frame := range render.Replay(ranks, instructions) {
+		f, err := os.Create(filepath.Join(dir, fmt.Sprintf("frame-%04d.%s", frame.Step, ext)))
+		if err != nil {
+			return fmt.Errorf("creating file: %v", err)
+		}
+
+		err = write(f, frame)
+		f.Close()
+		if err != nil {
+			return fmt.Errorf("writing frame %d: %v", frame.Step, err)
+		}
+	}
+
+	return nil
+}
+
+// exportGIF writes the replay as an animated GIF.
+func exportGIF(file string, ranks []int, instructions []pushswap.Operation, opts render.GIFOptions) error {
+	f, err := os.Create(file)
+	if err != nil {
+		return fmt.Errorf("creating file: %v", err)
+	}
+
+	defer f.Close()
+	return render.WriteGIF(f, ranks, instructions, opts)
+}
+
+// player steps through the instructions on stacks holding the ranks of the numbers.
+type player struct {
+	stacks       *pushswap.DoubleStack[int]
+	instructions []pushswap.Operation
+	// applied holds what every executed step actually did, so that steps
+	// which could not be applied are not undone when stepping back.
+	applied []pushswap.Operation
+	size    int
+	playing bool
+	delay   time.Duration
+}
+
+func (p *player) step() int {
+	return len(p.applied)
+}
+
+// forward executes the next instruction, returning false at the end.
+func (p *player) forward() bool {
+	if p.step() >= len(p.instructions) {
+		return false
+	}
+
+	p.applied = append(p.applied, p.stacks.ExecuteInstruction(p.instructions[p.step()]))
+	return true
+}
+
+// back undoes the last instruction, returning false at the start.
+func (p *player) back() bool {
+	if p.step() == 0 {
+		return false
+	}
+
+	last := p.applied[len(p.applied)-1]
+	p.applied = p.applied[:len(p.applied)-1]
+	p.stacks.ExecuteInstruction(last.Inverse())
+	return true
+}
+
+// bar draws a rank as a bar proportional to its size.
+func (p *player) bar(rank int, found bool, width int) string {
+	if !found {
+		return strings.Repeat(" ", width)
+	}
+
+	length := max(1, (rank+1)*width/max(1, p.size))
+	return strings.Repeat("█", length) + strings.Repeat(" ", width-length)
+}
+
+// draw renders the current state of both stacks, showing at most `rows` values of each.
+func (p *player) draw(output *bufio.Writer, width, rows int) {
+	status := "paused"
+	if p.playing {
+		status = "playing"
+	}
+
+	op := pushswap.Operation("start")
+	if p.step() > 0 {
+		op = p.instructions[p.step()-1]
+		if p.applied[p.step()-1] == pushswap.Invalid {
+			op += " (not applied)"
+		}
+	}
+
+	output.WriteString("\x1b[H\x1b[2J")
+	fmt.Fprintf(output, "step %d/%d  %-18s  %-7s  delay %v\n", p.step(), len(p.instructions), op, status, p.delay)
+	fmt.Fprintf(output, "%-*s   %s\n", width, "A", "B")
+
+	height := max(p.stacks.A.Len(), p.stacks.B.Len())
+	if rows > 0 && height > rows {
+		height = rows
+	}
+
+	for i := range height {
+		a, foundA := p.stacks.A.Index(i)
+		b, foundB := p.stacks.B.Index(i)
+
+		fmt.Fprintf(output, "%s | %s\n", p.bar(a, foundA, width), p.bar(b, foundB, width))
+	}
+
+	output.Flush()
+}
+
+func main() {
+	var numbers cli.NumberFlags
+	numbers.Register(flag.CommandLine)
+	numbersFile := flag.String("numbers", "", "read the numbers from this file instead of the command line")
+	instructionsFile := flag.String("instructions", "-", "file to read the instructions from")
+	delay := flag.Duration("delay", 50*time.Millisecond, "time between instructions while playing")
+	width := flag.Int("width", 40, "maximum width of the bars of each stack")
+	height := flag.Int("height", 0, "maximum number of values drawn per stack (default: terminal height)")
+	paused := flag.Bool("paused", false, "start paused on the initial state")
+	noControls := flag.Bool("no-controls", false, "play the instructions once without reading keys from the terminal")
+	htmlFile := flag.String("html", "", "write the replay to this file as a self-contained HTML page and exit")
+	svgDir := flag.String("svg", "", "write every frame of the replay to this directory as SVG images and exit")
+	pngDir := flag.String("png", "", "write every frame of the replay to this directory as PNG images and exit")
+	gifFile := flag.String("gif", "", "write the replay to this file as an animated GIF and exit")
+	gifEvery := flag.Int("gif-every", 1, "draw only every n-th instruction in the GIF")
+	gifMaxFrames := flag.Int("gif-max-frames", 300, "skip instructions to keep the GIF below this many frames (0: no limit)")
+
+	flag.Usage = printHelp
+	args, err := cli.ParseFlags(flag.CommandLine, os.Args[1:])
+	if err != nil {
+		log.Fatalln("ERROR:", err)
+	}
+
+	if err := numbers.Check(flag.CommandLine); err != nil {
+		log.Fatalln("ERROR:", err)
+	}
+
+	if *numbersFile != "" && len(args) > 0 {
+		log.Fatalln("ERROR: numbers on the command line cannot be combined with -numbers")
+	}
+
+	// Only the order of the numbers matters, so they are replaced by their
+	// ranks whatever their type.
+	var ranks []int
+	switch numbers.Type {
+	case "int":
+		ranks, err = readRanks(cli.TokenParser(&numbers, pushswap.ParseInt), *numbersFile, args)
+	case "bigint":
+		ranks, err = readRanks(cli.TokenParser(&numbers, pushswap.ParseBigInt), *numbersFile, args)
+	case "string":
+		ranks, err = readRanks(cli.TokenParser(&numbers, pushswap.ParseString), *numbersFile, args)
+	case "version":
+		ranks, err = readRanks(cli.TokenParser(&numbers, pushswap.ParseVersion), *numbersFile, args)
+	default:
+		ranks, err = readRanks(cli.FloatParser(&numbers), *numbersFile, args)
+	}
+
+	if err != nil {
+		log.Fatalln("ERROR:", err)
+	}
+
+	instructions, err := readInstructions(*instructionsFile)
+	if err != nil {
+		log.Fatalln("ERROR:", err)
+	}
+
+	if *htmlFile != "" || *svgDir != "" || *pngDir != "" || *gifFile != "" {
+		if *htmlFile != "" {
+			err = exportHTML(*htmlFile, ranks, instructions)
+			if err != nil {
+				log.Fatalln("ERROR:", err)
+			}
+		}
+
+		if *svgDir != "" {
+			err = exportFrames(*svgDir, "svg", ranks, instructions, render.WriteSVG)
+			if err != nil {
+				log.Fatalln("ERROR:", err)
+			}
+		}
+
+		if *pngDir != "" {
+			writePNG := func(w io.Writer, f render.Frame) error {
+				return render.WritePNG(w, f, len(instructions))
+			}
+
+			err = exportFrames(*pngDir, "png", ranks, instructions, writePNG)
+			if err != nil {
+				log.Fatalln("ERROR:", err)
+			}
+		}
+
+		if *gifFile != "" {
+			opts := render.GIFOptions{
+				Every:     *gifEvery,
+				MaxFrames: *gifMaxFrames,
+				Delay:     *delay,
+				LastDelay: 2 * time.Second,
+			}
+
+			err = exportGIF(*gifFile, ranks, instructions, opts)
+			if err != nil {
+				log.Fatalln("ERROR:", err)
+			}
+		}
+
+		return
+	}
+
+	p := &player{
+		stacks:       pushswap.NewDoubleStack(ranks...),
+		instructions: instructions,
+		size:         len(ranks),
+		playing:      !*paused,
+		delay:        min(max(*delay, minDelay), maxDelay),
+	}
+
+	// Without a terminal to read keys from, the instructions are played once.
+	var keys <-chan key
+	var term *terminal
+	rows := *height
+
+	if !*noControls {
+		term, err = openTerminal()
+		if err != nil {
+			log.Println("WARNING: controls disabled:", err)
+		}
+	}
+
+	if term == nil {
+		p.playing = true
+	} else {
+		defer term.close()
+		keys = term.keys()
+
+		if rows <= 0 {
+			rows = term.rows() - 3
+		}
+
+		// Restore the terminal when interrupted.
+		interrupts := make(chan os.Signal, 1)
+		signal.Notify(interrupts, os.Interrupt)
+		go func() {
+			<-interrupts
+			term.close()
+			fmt.Print("\x1b[?25h")
+			os.Exit(130)
+		}()
+	}
+
+	output := bufio.NewWriter(os.Stdout)
+	fmt.Fprint(output, "\x1b[?25l")
+	defer fmt.Print("\x1b[?25h")
+
+	ticker := time.NewTicker(p.delay)
+	defer ticker.Stop()
+
+	p.draw(output, *width, rows)
+	for {
+		if p.step() >= len(p.instructions) && term == nil {
+			return
+		}
+
+		select {
+		case <-ticker.C:
+			if !p.playing {
+				continue
+			}
+
+			p.forward()
+		case k, ok := <-keys:
+			if !ok {
+				return
+			}
+
+			switch k {
+			case keyPlayPause:
+				p.playing = !p.playing && p.step() < len(p.instructions)
+			case keyForward:
+				p.playing = false
+				p.forward()
+			case keyBack:
+				p.playing = false
+				p.back()
+			case keyFaster:
+				p.delay = max(p.delay/2, minDelay)
+				ticker.Reset(p.delay)
+			case keySlower:
+				p.delay = min(p.delay*2, maxDelay)
+				ticker.Reset(p.delay)
+			case keyQuit:
+				return
+			}
+		}
+
+		if p.step() >= len(p.instructions) {
+			p.playing = false
+		}
+
+		p.draw(output, *width, rows)
+	}
+}
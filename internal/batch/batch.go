@@ -0,0 +1,72 @@
+// Package batch processes lists of inputs concurrently while keeping the
+// results in the order of the inputs.
+package batch
+
+import (
+	"runtime"
+	"sync"
+)
+
+// Jobs returns the number of workers to use for a -jobs flag value, where
+// values below 1 mean one worker per CPU.
+func Jobs(n int) int {
+	if n < 1 {
+		return runtime.NumCPU()
+	}
+
+	return n
+}
+
+// Run calls work on every item using up to `jobs` goroutines and calls emit
+// with the index and result of every item in order, as soon as the results
+// of all the items before it are emitted. emit is only called from the
+// goroutine calling Run, so it can write output without locking.
+func Run[In, Out any](items []In, jobs int, work func(In) Out, emit func(int, Out)) {
+	if len(items) == 0 {
+		return
+	}
+
+	jobs = min(Jobs(jobs), len(items))
+	if jobs == 1 {
+		for i, item := range items {
+			emit(i, work(item))
+		}
+
+		return
+	}
+
+	// Every item gets its own channel so that results can be emitted in
+	// order while later items are still being processed.
+	results := make([]chan Out, len(items))
+	for i := range results {
+		results[i] = make(chan Out, 1)
+	}
+
+	indices := make(chan int)
+	var wg sync.WaitGroup
+
+	for range jobs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for i := range indices {
+				results[i] <- work(items[i])
+			}
+		}()
+	}
+
+	go func() {
+		for i := range items {
+			indices <- i
+		}
+
+		close(indices)
+	}()
+
+	for i, result := range results {
+		emit(i, <-result)
+	}
+
+	wg.Wait()
+}
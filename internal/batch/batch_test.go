@@ -0,0 +1,87 @@
+package batch
+
+import (
+	"runtime"
+	"slices"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRun(t *testing.T) {
+	tests := []struct {
+		name  string
+		items int
+		jobs  int
+	}{
+		{name: "no items", items: 0, jobs: 4},
+		{name: "sequential", items: 10, jobs: 1},
+		{name: "parallel", items: 100, jobs: 8},
+		{name: "more jobs than items", items: 3, jobs: 16},
+		{name: "one job per CPU", items: 20, jobs: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			items := make([]int, tt.items)
+			for i := range items {
+				items[i] = i
+			}
+
+			var indices, results []int
+			Run(items, tt.jobs, func(n int) int {
+				// Later items finish first to check the order of the results.
+				time.Sleep(time.Duration(tt.items-n) * 10 * time.Microsecond)
+				return n * n
+			}, func(i, result int) {
+				indices = append(indices, i)
+				results = append(results, result)
+			})
+
+			for i := range items {
+				if i >= len(results) || indices[i] != i || results[i] != i*i {
+					t.Fatalf("emitted indices %v and results %v out of order", indices, results)
+				}
+			}
+
+			if len(results) != tt.items {
+				t.Errorf("emitted %d results, want %d", len(results), tt.items)
+			}
+		})
+	}
+}
+
+func TestRunConcurrency(t *testing.T) {
+	const jobs = 4
+
+	var running, peak atomic.Int32
+	items := slices.Repeat([]int{0}, 40)
+
+	Run(items, jobs, func(int) int {
+		n := running.Add(1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+
+		time.Sleep(time.Millisecond)
+		running.Add(-1)
+		return 0
+	}, func(int, int) {})
+
+	if got := peak.Load(); got > jobs {
+		t.Errorf("%d items processed at once, want at most %d", got, jobs)
+	}
+}
+
+func TestJobs(t *testing.T) {
+	if got := Jobs(3); got != 3 {
+		t.Errorf("Jobs(3) = %d, want 3", got)
+	}
+
+	if got := Jobs(0); got != runtime.NumCPU() {
+		t.Errorf("Jobs(0) = %d, want %d", got, runtime.NumCPU())
+	}
+}
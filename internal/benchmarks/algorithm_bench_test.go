@@ -20,11 +20,10 @@ import (
 	"cmp"
 	"context"
 	"fmt"
-	"math"
-	"math/rand"
 	"testing"
 	"time"
 
+	"push-swap-go/internal/gen"
 	"push-swap-go/internal/pushswap"
 )
 
@@ -51,97 +50,6 @@ func runWithTimeout[T cmp.Ordered](ctx context.Context, algo AlgorithmFunc[T], d
 	}
 }
 
-func generateRandomInts(n, min, max int, seed int64) []int {
-	rng := rand.New(rand.NewSource(seed))
-	data := make([]int, n)
-
-	for i := range data {
-		data[i] = rng.Intn(max-min+1) + min
-	}
-
-	return data
-}
-
-func generateRandomFloats(n int, min, max float64, seed int64) []float64 {
-	rng := rand.New(rand.NewSource(seed))
-	data := make([]float64, n)
-
-	for i := range data {
-		data[i] = min + rng.Float64()*(max-min)
-	}
-
-	return data
-}
-
-func generateFloatsWithDuplicates(n, dupPercent int, seed int64) []float64 {
-	rng := rand.New(rand.NewSource(seed))
-	data := make([]float64, n)
-	duplicateValue := rng.Float64() * 10000.0
-	numDuplicates := (n * dupPercent) / 100
-
-	for i := 0; i < numDuplicates; i++ {
-		data[i] = duplicateValue
-	}
-
-	for i := numDuplicates; i < n; i++ {
-		data[i] = rng.Float64() * 10000.0
-	}
-
-	rng.Shuffle(n, func(i, j int) {
-		data[i], data[j] = data[j], data[i]
-	})
-
-	return data
-}
-
-func generateNearlySorted(n, clusterSize int, position string, seed int64) []float64 {
-	rng := rand.New(rand.NewSource(seed))
-	data := make([]float64, n)
-
-	for i := range data {
-		data[i] = float64(i)
-	}
-
-	cluster := make([]float64, clusterSize)
-
-	for i := range cluster {
-		cluster[i] = rng.Float64() * float64(n)
-	}
-
-	insertPos := 0
-	switch position {
-	case "middle":
-		insertPos = n / 2
-	case "bottom":
-		insertPos = n - clusterSize
-	}
-
-	copy(data[insertPos:insertPos+clusterSize], cluster)
-	return data
-}
-
-func generateTinyFloats(n int, seed int64) []float64 {
-	rng := rand.New(rand.NewSource(seed))
-	data := make([]float64, n)
-
-	for i := range data {
-		data[i] = 0.0001 + rng.Float64()*0.0008
-	}
-
-	return data
-}
-
-func generateMassiveFloats(n int, seed int64) []float64 {
-	rng := rand.New(rand.NewSource(seed))
-	data := make([]float64, n)
-
-	for i := range data {
-		data[i] = math.MaxInt32 + rng.Float64()*math.MaxInt32
-	}
-
-	return data
-}
-
 func reportInstructions(b *testing.B, total, completed int) {
 	if completed == 0 {
 		return
@@ -186,7 +94,7 @@ func BenchmarkTurkAlgorithm_MassiveScale(b *testing.B) {
 		b.Run(fmt.Sprintf("%d", size), func(b *testing.B) {
 			datasets := make([][]int, b.N)
 			for i := 0; i < b.N; i++ {
-				datasets[i] = generateRandomInts(size, -100000, 100000, int64(i))
+				datasets[i], _ = gen.RandomInts(size, -100000, 100000, int64(i))
 			}
 
 			runTimedBenchmark(b, datasets, pushswap.TurkAlgorithm[int])
@@ -200,7 +108,7 @@ func BenchmarkTurkAlgorithm_StandardFloats(b *testing.B) {
 		b.Run(fmt.Sprintf("%d", size), func(b *testing.B) {
 			datasets := make([][]float64, b.N)
 			for i := 0; i < b.N; i++ {
-				datasets[i] = generateRandomFloats(size, -10000, 10000, int64(i))
+				datasets[i] = gen.RandomFloats(size, -10000, 10000, int64(i))
 			}
 
 			runTimedBenchmark(b, datasets, pushswap.TurkAlgorithm[float64])
@@ -214,7 +122,7 @@ func BenchmarkTurkAlgorithm_HighDensityDuplicates(b *testing.B) {
 		b.Run(fmt.Sprintf("%d", size), func(b *testing.B) {
 			datasets := make([][]float64, b.N)
 			for i := 0; i < b.N; i++ {
-				datasets[i] = generateFloatsWithDuplicates(size, 50, int64(i))
+				datasets[i] = gen.FloatsWithDuplicates(size, 50, int64(i))
 			}
 
 			runTimedBenchmark(b, datasets, pushswap.TurkAlgorithm[float64])
@@ -223,24 +131,24 @@ func BenchmarkTurkAlgorithm_HighDensityDuplicates(b *testing.B) {
 }
 
 func BenchmarkTurkAlgorithm_NearlySorted_TopHeavy(b *testing.B) {
-	benchmarkNearlySorted(b, "top")
+	benchmarkNearlySorted(b, gen.Top)
 }
 
 func BenchmarkTurkAlgorithm_NearlySorted_MiddleHeavy(b *testing.B) {
-	benchmarkNearlySorted(b, "middle")
+	benchmarkNearlySorted(b, gen.Middle)
 }
 
 func BenchmarkTurkAlgorithm_NearlySorted_BottomHeavy(b *testing.B) {
-	benchmarkNearlySorted(b, "bottom")
+	benchmarkNearlySorted(b, gen.Bottom)
 }
 
-func benchmarkNearlySorted(b *testing.B, position string) {
+func benchmarkNearlySorted(b *testing.B, position gen.Position) {
 	sizes := []int{500, 750, 1000}
 	for _, size := range sizes {
 		b.Run(fmt.Sprintf("%d", size), func(b *testing.B) {
 			datasets := make([][]float64, b.N)
 			for i := 0; i < b.N; i++ {
-				datasets[i] = generateNearlySorted(size, 10, position, int64(i))
+				datasets[i] = gen.NearlySorted(size, 10, position, int64(i))
 			}
 
 			runTimedBenchmark(b, datasets, pushswap.TurkAlgorithm[float64])
@@ -254,7 +162,7 @@ func BenchmarkTurkAlgorithm_TinyFloats(b *testing.B) {
 		b.Run(fmt.Sprintf("%d", size), func(b *testing.B) {
 			datasets := make([][]float64, b.N)
 			for i := 0; i < b.N; i++ {
-				datasets[i] = generateTinyFloats(size, int64(i))
+				datasets[i] = gen.TinyFloats(size, int64(i))
 			}
 
 			runTimedBenchmark(b, datasets, pushswap.TurkAlgorithm[float64])
@@ -268,7 +176,7 @@ func BenchmarkTurkAlgorithm_MassiveFloats(b *testing.B) {
 		b.Run(fmt.Sprintf("%d", size), func(b *testing.B) {
 			datasets := make([][]float64, b.N)
 			for i := 0; i < b.N; i++ {
-				datasets[i] = generateMassiveFloats(size, int64(i))
+				datasets[i] = gen.MassiveFloats(size, int64(i))
 			}
 
 			runTimedBenchmark(b, datasets, pushswap.TurkAlgorithm[float64])
@@ -281,11 +189,11 @@ func BenchmarkTurkAlgorithm_AllScenarios(b *testing.B) {
 		name      string
 		generator func(seed int64) []float64
 	}{
-		{"Random_1000", func(seed int64) []float64 { return generateRandomFloats(1000, -10000, 10000, seed) }},
-		{"Duplicates_1000", func(seed int64) []float64 { return generateFloatsWithDuplicates(1000, 50, seed) }},
-		{"NearlySorted_1000", func(seed int64) []float64 { return generateNearlySorted(1000, 10, "middle", seed) }},
-		{"TinyFloats_1000", func(seed int64) []float64 { return generateTinyFloats(1000, seed) }},
-		{"MassiveFloats_1000", func(seed int64) []float64 { return generateMassiveFloats(1000, seed) }},
+		{"Random_1000", func(seed int64) []float64 { return gen.RandomFloats(1000, -10000, 10000, seed) }},
+		{"Duplicates_1000", func(seed int64) []float64 { return gen.FloatsWithDuplicates(1000, 50, seed) }},
+		{"NearlySorted_1000", func(seed int64) []float64 { return gen.NearlySorted(1000, 10, gen.Middle, seed) }},
+		{"TinyFloats_1000", func(seed int64) []float64 { return gen.TinyFloats(1000, seed) }},
+		{"MassiveFloats_1000", func(seed int64) []float64 { return gen.MassiveFloats(1000, seed) }},
 	}
 
 	for _, scenario := range scenarios {
@@ -299,3 +207,34 @@ func BenchmarkTurkAlgorithm_AllScenarios(b *testing.B) {
 		})
 	}
 }
+
+// BenchmarkDoubleStack_Observer replays a solution with and without an
+// observer attached. "Unset" must match the cost of the operations before
+// observers existed; the Turk algorithm benchmarks above run without one too.
+func BenchmarkDoubleStack_Observer(b *testing.B) {
+	nums := gen.RandomFloats(1000, -10000, 10000, 1)
+	instructions := pushswap.TurkAlgorithm(nums)
+
+	observers := []struct {
+		name     string
+		observer pushswap.Observer[float64]
+	}{
+		{"Unset", nil},
+		{"Attached", pushswap.ObserverFunc[float64](func(pushswap.Event[float64]) {})},
+	}
+
+	for _, o := range observers {
+		b.Run(o.name, func(b *testing.B) {
+			for b.Loop() {
+				b.StopTimer()
+				ds := pushswap.NewDoubleStack(nums...)
+				ds.SetObserver(o.observer)
+				b.StartTimer()
+
+				ds.ExecuteInstructions(instructions)
+			}
+
+			b.ReportMetric(float64(len(instructions)), "inst/op")
+		})
+	}
+}
@@ -0,0 +1,220 @@
+// Package cli holds the command line plumbing shared by the commands that
+// read number lists: the flags choosing how numbers are parsed and the
+// parsers reading them from arguments and files.
+package cli
+
+import (
+	"cmp"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"push-swap-go/internal/pushswap"
+)
+
+// ValueTypes are the values -type accepts.
+var ValueTypes = []string{"int", "float", "bigint", "string", "version"}
+
+// NumberFlags holds the flags choosing which values are read and how.
+type NumberFlags struct {
+	AllowDuplicates bool
+	Mode            pushswap.NumberMode // Only used for floats.
+	Equality        pushswap.Equality   // Only used for floats.
+	Type            string
+	Format          pushswap.InputFormat // Empty to pick it from the file extension.
+	bigint          bool
+}
+
+// Register defines the flags on fs.
+func (f *NumberFlags) Register(fs *flag.FlagSet) {
+	f.Mode = pushswap.ModeFinite
+	f.Equality = pushswap.EqualNumeric
+
+	fs.BoolVar(&f.AllowDuplicates, "allow-duplicates", false, "allow duplicate values in the number list")
+	fs.Var(&f.Mode, "number-mode", "numbers accepted with -type float: int32 (the classic rules), finite or any")
+	fs.Var(&f.Equality, "equality", "numbers counting as duplicates with -type float: numeric (-0 equals 0) or bitwise (-0 differs from 0)")
+	fs.StringVar(&f.Type, "type", "float", "type of the values to sort: int, float, bigint (integers of any size), string or version (like 1.2.10)")
+	fs.BoolVar(&f.bigint, "bigint", false, "same as -type bigint")
+	fs.Var(&f.Format, "input-format", "format of the number files: text (space separated), json (an array) or csv (default from the file extension, else text)")
+}
+
+// Check applies -bigint and rejects invalid combinations of the flags once
+// fs is parsed.
+func (f *NumberFlags) Check(fs *flag.FlagSet) error {
+	if f.bigint {
+		f.Type = "bigint"
+	}
+
+	if !slices.Contains(ValueTypes, f.Type) {
+		return fmt.Errorf("unknown -type %q, want %s", f.Type, strings.Join(ValueTypes, ", "))
+	}
+
+	if f.Type != "float" && (IsSet(fs, "number-mode") || IsSet(fs, "equality")) {
+		return fmt.Errorf("-number-mode and -equality only apply to -type float")
+	}
+
+	return nil
+}
+
+// Options returns the options for parsing floats.
+func (f *NumberFlags) Options() pushswap.ParseOptions {
+	return pushswap.ParseOptions{Mode: f.Mode, AllowDuplicates: f.AllowDuplicates, Equality: f.Equality}
+}
+
+// IsSet reports whether the flag was given on the command line.
+func IsSet(fs *flag.FlagSet, name string) bool {
+	set := false
+	fs.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			set = true
+		}
+	})
+
+	return set
+}
+
+// Parser parses number tokens into the values to sort, either given on the
+// command line or while reading them from a file.
+type Parser[T cmp.Ordered] struct {
+	parse  func(numStrings []string) ([]T, error)
+	read   func(tokens pushswap.TokenReader) ([]T, error)
+	format pushswap.InputFormat
+}
+
+// TokenParser returns a Parser parsing every token with parse.
+func TokenParser[T cmp.Ordered](f *NumberFlags, parse func(string) (T, error)) Parser[T] {
+	allowDups := f.AllowDuplicates
+
+	return Parser[T]{
+		parse: func(numStrings []string) ([]T, error) {
+			return pushswap.ParseSlice(numStrings, allowDups, parse)
+		},
+		read: func(tokens pushswap.TokenReader) ([]T, error) {
+			return pushswap.ReadSliceFrom(tokens, allowDups, parse)
+		},
+		format: f.Format,
+	}
+}
+
+// FloatParser returns a Parser parsing the numbers the options of the flags
+// accept.
+func FloatParser(f *NumberFlags) Parser[float64] {
+	opts := f.Options()
+
+	return Parser[float64]{
+		parse: func(numStrings []string) ([]float64, error) {
+			return pushswap.ParseNumberSliceWithOptions(numStrings, opts)
+		},
+		read: func(tokens pushswap.TokenReader) ([]float64, error) {
+			return pushswap.ReadNumbersFrom(tokens, opts)
+		},
+		format: f.Format,
+	}
+}
+
+// ArgTokens splits the command line arguments into number tokens, so that
+// both `3 2 1` and `"3 2 1"` work.
+func ArgTokens(args []string) []string {
+	var numStrings []string
+	for _, a := range args {
+		numStrings = append(numStrings, strings.Fields(a)...)
+	}
+
+	return numStrings
+}
+
+// ParseFlags parses the flags in args and returns the remaining arguments.
+// Parsing stops at the first negative number as well, so that numbers like
+// `-5 3 2` need no `--` before them, as with the original programs.
+func ParseFlags(fs *flag.FlagSet, args []string) ([]string, error) {
+	end := len(args)
+
+scan:
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+
+		switch {
+		case arg == "--" || arg == "-" || !strings.HasPrefix(arg, "-"):
+			break scan
+		case isNumber(arg):
+			end = i
+			break scan
+		case !strings.Contains(arg, "="):
+			// Skip the value of a non-boolean flag, which may be negative.
+			f := fs.Lookup(strings.TrimLeft(arg, "-"))
+			if f != nil && !isBoolFlag(f) {
+				i++
+			}
+		}
+	}
+
+	if err := fs.Parse(args[:end]); err != nil {
+		return nil, err
+	}
+
+	return append(fs.Args(), args[end:]...), nil
+}
+
+// isNumber reports whether the first token of an argument is a number.
+func isNumber(arg string) bool {
+	fields := strings.Fields(arg)
+	if len(fields) == 0 {
+		return false
+	}
+
+	_, err := strconv.ParseFloat(fields[0], 64)
+	return err == nil || errors.Is(err, strconv.ErrRange)
+}
+
+// isBoolFlag reports whether a flag takes no value, like those defined with
+// flag.Bool.
+func isBoolFlag(f *flag.Flag) bool {
+	b, ok := f.Value.(interface{ IsBoolFlag() bool })
+	return ok && b.IsBoolFlag()
+}
+
+// ParseArgs parses the number tokens of the command line arguments and
+// shows where the offending token is below the message on errors.
+func (p Parser[T]) ParseArgs(args []string) ([]T, error) {
+	numStrings := ArgTokens(args)
+	numbers, err := p.parse(numStrings)
+
+	if highlight := pushswap.Highlight(numStrings, err); highlight != "" {
+		return nil, fmt.Errorf("%w\n%s", err, highlight)
+	}
+
+	return numbers, err
+}
+
+// ReadFile parses the numbers of a file, or of stdin for "-", while reading
+// it, so that its lines can be of any length. The format of the file is
+// picked from its extension unless set.
+func (p Parser[T]) ReadFile(file string) ([]T, error) {
+	input := os.Stdin
+
+	if file != "-" {
+		f, err := os.Open(file)
+		if err != nil {
+			return nil, fmt.Errorf("opening file: %v", err)
+		}
+
+		defer f.Close()
+		input = f
+	}
+
+	format := p.format
+	if format == "" {
+		format = pushswap.FormatOf(file)
+	}
+
+	tokens, err := pushswap.NewTokenReader(input, format)
+	if err != nil {
+		return nil, err
+	}
+
+	return p.read(tokens)
+}
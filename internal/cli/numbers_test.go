@@ -0,0 +1,219 @@
+package cli
+
+import (
+	"errors"
+	"flag"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"push-swap-go/internal/pushswap"
+)
+
+// parseFlags registers the number flags on a new flag set and parses args.
+func parseFlags(t *testing.T, args ...string) (*NumberFlags, *flag.FlagSet) {
+	t.Helper()
+
+	var f NumberFlags
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	f.Register(fs)
+
+	if err := fs.Parse(args); err != nil {
+		t.Fatalf("Parse(%q) error = %v", args, err)
+	}
+
+	return &f, fs
+}
+
+func TestNumberFlagsCheck(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		wantType string
+		wantErr  string
+	}{
+		{name: "defaults", wantType: "float"},
+		{name: "bigint", args: []string{"-bigint"}, wantType: "bigint"},
+		{name: "float options", args: []string{"-number-mode", "int32", "-equality", "bitwise"}, wantType: "float"},
+		{name: "unknown type", args: []string{"-type", "complex"}, wantErr: `unknown -type "complex"`},
+		{name: "number mode for ints", args: []string{"-type", "int", "-number-mode", "int32"}, wantErr: "only apply to -type float"},
+		{name: "equality for strings", args: []string{"-type", "string", "-equality", "bitwise"}, wantErr: "only apply to -type float"},
+		{name: "number mode for bigint", args: []string{"-bigint", "-number-mode", "any"}, wantErr: "only apply to -type float"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, fs := parseFlags(t, tt.args...)
+
+			err := f.Check(fs)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Check() error = %v, want it to contain %q", err, tt.wantErr)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Check() error = %v", err)
+			}
+
+			if f.Type != tt.wantType {
+				t.Errorf("Type = %q, want %q", f.Type, tt.wantType)
+			}
+		})
+	}
+}
+
+func TestNumberFlagsOptions(t *testing.T) {
+	f, _ := parseFlags(t, "-allow-duplicates", "-equality", "bitwise")
+
+	want := pushswap.ParseOptions{Mode: pushswap.ModeFinite, AllowDuplicates: true, Equality: pushswap.EqualBitwise}
+	if got := f.Options(); got != want {
+		t.Errorf("Options() = %+v, want %+v", got, want)
+	}
+}
+
+func TestIsSet(t *testing.T) {
+	_, fs := parseFlags(t, "-number-mode", "finite")
+
+	if !IsSet(fs, "number-mode") {
+		t.Error("IsSet(number-mode) = false, want true")
+	}
+
+	if IsSet(fs, "equality") {
+		t.Error("IsSet(equality) = true, want false")
+	}
+}
+
+func TestParseArgs(t *testing.T) {
+	f, _ := parseFlags(t)
+	parse := FloatParser(f)
+
+	got, err := parse.ParseArgs([]string{"3 1", "2"})
+	if err != nil {
+		t.Fatalf("ParseArgs() error = %v", err)
+	}
+
+	if want := []float64{3, 1, 2}; !slices.Equal(got, want) {
+		t.Errorf("ParseArgs() = %v, want %v", got, want)
+	}
+
+	_, err = parse.ParseArgs([]string{"3 x", "2"})
+
+	var parseErr *pushswap.ParseError
+	if !errors.As(err, &parseErr) || parseErr.Index != 1 {
+		t.Fatalf("ParseArgs() error = %v, want a ParseError at index 1", err)
+	}
+
+	if lines := strings.Split(err.Error(), "\n"); len(lines) < 3 {
+		t.Errorf("ParseArgs() error = %q, want the tokens highlighted below the message", err)
+	}
+}
+
+func TestReadFile(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"numbers.txt":  "3 1\n2\n",
+		"numbers.json": "[3, 1, 2]",
+		"numbers.csv":  "3,1\n2\n",
+		"json.txt":     "[3, 1, 2]",
+	}
+
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name    string
+		file    string
+		args    []string
+		wantErr bool
+	}{
+		{name: "text", file: "numbers.txt"},
+		{name: "json from the extension", file: "numbers.json"},
+		{name: "csv from the extension", file: "numbers.csv"},
+		{name: "json set", file: "json.txt", args: []string{"-input-format", "json"}},
+		{name: "json read as text", file: "json.txt", wantErr: true},
+		{name: "missing file", file: "missing.txt", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, _ := parseFlags(t, tt.args...)
+
+			got, err := TokenParser(f, pushswap.ParseInt).ReadFile(filepath.Join(dir, tt.file))
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("ReadFile() = %v, want an error", got)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("ReadFile() error = %v", err)
+			}
+
+			if want := []int{3, 1, 2}; !slices.Equal(got, want) {
+				t.Errorf("ReadFile() = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestParseFlags(t *testing.T) {
+	tests := []struct {
+		name      string
+		args      []string
+		want      []string
+		wantDups  bool
+		wantMode  pushswap.NumberMode
+		wantError bool
+	}{
+		{name: "numbers only", args: []string{"3", "2", "1"}, want: []string{"3", "2", "1"}},
+		{name: "negative first number", args: []string{"-5", "3", "2"}, want: []string{"-5", "3", "2"}},
+		{name: "quoted negative numbers", args: []string{"-allow-duplicates", "-5 3 -5"}, want: []string{"-5 3 -5"}, wantDups: true},
+		{name: "flag value before the numbers", args: []string{"-number-mode", "int32", "-1", "2"}, want: []string{"-1", "2"}, wantMode: pushswap.ModeInt32},
+		{name: "double dash", args: []string{"--", "-1e3"}, want: []string{"-1e3"}},
+		{name: "unknown flag", args: []string{"-x", "1"}, wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var f NumberFlags
+			fs := flag.NewFlagSet("test", flag.ContinueOnError)
+			fs.SetOutput(io.Discard)
+			f.Register(fs)
+
+			got, err := ParseFlags(fs, tt.args)
+			if tt.wantError {
+				if err == nil {
+					t.Fatalf("ParseFlags(%q) = %q, want an error", tt.args, got)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("ParseFlags(%q) error = %v", tt.args, err)
+			}
+
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("ParseFlags(%q) = %q, want %q", tt.args, got, tt.want)
+			}
+
+			wantMode := tt.wantMode
+			if wantMode == "" {
+				wantMode = pushswap.ModeFinite
+			}
+
+			if f.AllowDuplicates != tt.wantDups || f.Mode != wantMode {
+				t.Errorf("flags = %+v, want allow-duplicates %v and mode %s", f, tt.wantDups, wantMode)
+			}
+		})
+	}
+}
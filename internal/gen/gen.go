@@ -0,0 +1,192 @@
+package gen
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+)
+
+// Position is where the unsorted cluster is placed in a nearly sorted list.
+type Position string
+
+const (
+	Top    Position = "top"
+	Middle Position = "middle"
+	Bottom Position = "bottom"
+)
+
+// RandomInts returns n integers drawn uniformly from [min, max], duplicates included.
+func RandomInts(n, min, max int, seed int64) ([]int, error) {
+	if min > max {
+		return nil, fmt.Errorf("min %d is greater than max %d", min, max)
+	}
+
+	rng := rand.New(rand.NewSource(seed))
+	data := make([]int, n)
+	rangeSize := uint64(max) - uint64(min) + 1
+
+	for i := range data {
+		switch {
+		case rangeSize == 0:
+			// The range covers every int.
+			data[i] = int(rng.Uint64())
+		case rangeSize > math.MaxInt:
+			data[i] = min + int(randUint64n(rng, rangeSize))
+		default:
+			data[i] = rng.Intn(int(rangeSize)) + min
+		}
+	}
+
+	return data, nil
+}
+
+// UniqueInts returns n distinct integers drawn uniformly from [min, max] in random order.
+func UniqueInts(n, min, max int, seed int64) ([]int, error) {
+	if min > max {
+		return nil, fmt.Errorf("min %d is greater than max %d", min, max)
+	}
+
+	rangeSize := uint64(max) - uint64(min) + 1
+	if rangeSize != 0 && uint64(n) > rangeSize {
+		return nil, fmt.Errorf("cannot pick %d unique numbers from [%d, %d]", n, min, max)
+	}
+
+	rng := rand.New(rand.NewSource(seed))
+	data := make([]int, 0, n)
+	seen := make(map[int]struct{}, n)
+
+	if rangeSize == 0 {
+		// The range covers every int, so collisions are vanishingly rare.
+		for len(data) < n {
+			val := int(rng.Uint64())
+			if _, exists := seen[val]; exists {
+				continue
+			}
+
+			seen[val] = struct{}{}
+			data = append(data, val)
+		}
+	} else {
+		// Floyd's sampling algorithm picks every value at most once in n draws.
+		for j := rangeSize - uint64(n); j < rangeSize; j++ {
+			val := min + int(randUint64n(rng, j+1))
+			if _, exists := seen[val]; exists {
+				val = min + int(j)
+			}
+
+			seen[val] = struct{}{}
+			data = append(data, val)
+		}
+	}
+
+	rng.Shuffle(len(data), func(i, j int) {
+		data[i], data[j] = data[j], data[i]
+	})
+
+	return data, nil
+}
+
+// randUint64n returns a uniform random number in [0, n).
+func randUint64n(rng *rand.Rand, n uint64) uint64 {
+	if n <= math.MaxInt64 {
+		return uint64(rng.Int63n(int64(n)))
+	}
+
+	// Rejection sampling for ranges wider than int64.
+	for {
+		v := rng.Uint64()
+		if v < n {
+			return v
+		}
+	}
+}
+
+// RandomFloats returns n floats drawn uniformly from [min, max).
+func RandomFloats(n int, min, max float64, seed int64) []float64 {
+	rng := rand.New(rand.NewSource(seed))
+	data := make([]float64, n)
+
+	for i := range data {
+		data[i] = min + rng.Float64()*(max-min)
+	}
+
+	return data
+}
+
+// FloatsWithDuplicates returns n floats in [0, 10000) where dupPercent percent
+// of them share a single value. dupPercent is clamped to [0, 100].
+func FloatsWithDuplicates(n, dupPercent int, seed int64) []float64 {
+	rng := rand.New(rand.NewSource(seed))
+	data := make([]float64, n)
+	dupPercent = min(max(dupPercent, 0), 100)
+	duplicateValue := rng.Float64() * 10000.0
+	numDuplicates := (n * dupPercent) / 100
+
+	for i := 0; i < numDuplicates; i++ {
+		data[i] = duplicateValue
+	}
+
+	for i := numDuplicates; i < n; i++ {
+		data[i] = rng.Float64() * 10000.0
+	}
+
+	rng.Shuffle(n, func(i, j int) {
+		data[i], data[j] = data[j], data[i]
+	})
+
+	return data
+}
+
+// NearlySorted returns the sorted values 0 to n-1 with a cluster of clusterSize
+// random values overwriting the given position. clusterSize is clamped to
+// [0, n].
+func NearlySorted(n, clusterSize int, position Position, seed int64) []float64 {
+	rng := rand.New(rand.NewSource(seed))
+	data := make([]float64, n)
+	clusterSize = min(max(clusterSize, 0), n)
+
+	for i := range data {
+		data[i] = float64(i)
+	}
+
+	cluster := make([]float64, clusterSize)
+
+	for i := range cluster {
+		cluster[i] = rng.Float64() * float64(n)
+	}
+
+	insertPos := 0
+	switch position {
+	case Middle:
+		insertPos = min(n/2, n-clusterSize)
+	case Bottom:
+		insertPos = n - clusterSize
+	}
+
+	copy(data[insertPos:insertPos+clusterSize], cluster)
+	return data
+}
+
+// TinyFloats returns n floats in [0.0001, 0.0009).
+func TinyFloats(n int, seed int64) []float64 {
+	rng := rand.New(rand.NewSource(seed))
+	data := make([]float64, n)
+
+	for i := range data {
+		data[i] = 0.0001 + rng.Float64()*0.0008
+	}
+
+	return data
+}
+
+// MassiveFloats returns n floats beyond the 32-bit integer range.
+func MassiveFloats(n int, seed int64) []float64 {
+	rng := rand.New(rand.NewSource(seed))
+	data := make([]float64, n)
+
+	for i := range data {
+		data[i] = math.MaxInt32 + rng.Float64()*math.MaxInt32
+	}
+
+	return data
+}
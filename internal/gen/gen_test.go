@@ -0,0 +1,209 @@
+package gen
+
+import (
+	"math"
+	"slices"
+	"testing"
+)
+
+func TestRandomInts(t *testing.T) {
+	got, err := RandomInts(1000, -5, 5, 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(got) != 1000 {
+		t.Fatalf("len = %d, want 1000", len(got))
+	}
+
+	for i, v := range got {
+		if v < -5 || v > 5 {
+			t.Errorf("[%d] = %d, want within [-5, 5]", i, v)
+		}
+	}
+
+	if again, _ := RandomInts(1000, -5, 5, 1); !slices.Equal(got, again) {
+		t.Errorf("same seed produced different output")
+	}
+	if other, _ := RandomInts(1000, -5, 5, 2); slices.Equal(got, other) {
+		t.Errorf("different seeds produced the same output")
+	}
+}
+
+func TestRandomIntsRanges(t *testing.T) {
+	tests := []struct {
+		name    string
+		min     int
+		max     int
+		wantErr bool
+	}{
+		{name: "single value", min: 7, max: 7},
+		{name: "wider than int64", min: math.MinInt64 + 1, max: math.MaxInt64},
+		{name: "full int range", min: math.MinInt, max: math.MaxInt},
+		{name: "min greater than max", min: 5, max: -5, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := RandomInts(100, tt.min, tt.max, 1)
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("RandomInts() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			for i, v := range got {
+				if v < tt.min || v > tt.max {
+					t.Errorf("[%d] = %d, want within [%d, %d]", i, v, tt.min, tt.max)
+				}
+			}
+		})
+	}
+}
+
+func TestUniqueInts(t *testing.T) {
+	tests := []struct {
+		name    string
+		n       int
+		min     int
+		max     int
+		wantErr bool
+	}{
+		{name: "empty", n: 0, min: 0, max: 10},
+		{name: "sparse range", n: 100, min: -1000000, max: 1000000},
+		{name: "whole range", n: 11, min: -5, max: 5},
+		{name: "single value", n: 1, min: 7, max: 7},
+		{name: "int32 range", n: 500, min: math.MinInt32, max: math.MaxInt32},
+		{name: "full int range", n: 10, min: math.MinInt, max: math.MaxInt},
+		{name: "more than the range holds", n: 12, min: -5, max: 5, wantErr: true},
+		{name: "min greater than max", n: 1, min: 5, max: -5, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := UniqueInts(tt.n, tt.min, tt.max, 42)
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("UniqueInts() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr {
+				return
+			}
+
+			if len(got) != tt.n {
+				t.Fatalf("len = %d, want %d", len(got), tt.n)
+			}
+
+			seen := map[int]bool{}
+			for _, v := range got {
+				if v < tt.min || v > tt.max {
+					t.Errorf("%d is outside [%d, %d]", v, tt.min, tt.max)
+				}
+				if seen[v] {
+					t.Errorf("%d appears more than once", v)
+				}
+				seen[v] = true
+			}
+
+			again, _ := UniqueInts(tt.n, tt.min, tt.max, 42)
+			if !slices.Equal(got, again) {
+				t.Errorf("same seed produced different output")
+			}
+		})
+	}
+}
+
+func TestRandomFloats(t *testing.T) {
+	got := RandomFloats(1000, -1.5, 2.5, 3)
+
+	for i, v := range got {
+		if v < -1.5 || v >= 2.5 {
+			t.Errorf("[%d] = %v, want within [-1.5, 2.5)", i, v)
+		}
+	}
+
+	if !slices.Equal(got, RandomFloats(1000, -1.5, 2.5, 3)) {
+		t.Errorf("same seed produced different output")
+	}
+}
+
+func TestFloatsWithDuplicates(t *testing.T) {
+	tests := []struct {
+		name       string
+		n          int
+		dupPercent int
+		wantDups   int
+	}{
+		{name: "no duplicates", n: 100, dupPercent: 0, wantDups: 1},
+		{name: "half duplicates", n: 100, dupPercent: 50, wantDups: 50},
+		{name: "all duplicates", n: 20, dupPercent: 100, wantDups: 20},
+		{name: "above 100 percent", n: 20, dupPercent: 150, wantDups: 20},
+		{name: "below 0 percent", n: 100, dupPercent: -10, wantDups: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := FloatsWithDuplicates(tt.n, tt.dupPercent, 5)
+			counts := map[float64]int{}
+			mostCommon := 0
+
+			for _, v := range got {
+				counts[v]++
+				mostCommon = max(mostCommon, counts[v])
+			}
+
+			if len(got) != tt.n {
+				t.Fatalf("len = %d, want %d", len(got), tt.n)
+			}
+			if mostCommon != tt.wantDups {
+				t.Errorf("most common value appears %d times, want %d", mostCommon, tt.wantDups)
+			}
+		})
+	}
+}
+
+func TestNearlySorted(t *testing.T) {
+	tests := []struct {
+		name        string
+		n           int
+		clusterSize int
+		position    Position
+		wantSorted  [2]int // Bounds of the part that must be left untouched.
+	}{
+		{name: "top", n: 100, clusterSize: 10, position: Top, wantSorted: [2]int{10, 100}},
+		{name: "middle", n: 100, clusterSize: 10, position: Middle, wantSorted: [2]int{0, 50}},
+		{name: "bottom", n: 100, clusterSize: 10, position: Bottom, wantSorted: [2]int{0, 90}},
+		{name: "cluster larger than list", n: 5, clusterSize: 10, position: Middle, wantSorted: [2]int{0, 0}},
+		{name: "negative cluster", n: 10, clusterSize: -1, position: Middle, wantSorted: [2]int{0, 10}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := NearlySorted(tt.n, tt.clusterSize, tt.position, 9)
+
+			if len(got) != tt.n {
+				t.Fatalf("len = %d, want %d", len(got), tt.n)
+			}
+
+			for i := tt.wantSorted[0]; i < tt.wantSorted[1]; i++ {
+				if got[i] != float64(i) {
+					t.Errorf("[%d] = %v, want %d", i, got[i], i)
+				}
+			}
+		})
+	}
+}
+
+func TestTinyAndMassiveFloats(t *testing.T) {
+	for i, v := range TinyFloats(1000, 11) {
+		if v < 0.0001 || v >= 0.0009 {
+			t.Errorf("TinyFloats()[%d] = %v, want within [0.0001, 0.0009)", i, v)
+		}
+	}
+
+	for i, v := range MassiveFloats(1000, 11) {
+		if v < math.MaxInt32 {
+			t.Errorf("MassiveFloats()[%d] = %v, want at least %d", i, v, math.MaxInt32)
+		}
+	}
+}
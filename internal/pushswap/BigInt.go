@@ -0,0 +1,121 @@
+package pushswap
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// bigIntLenDigits is the width of the digit count in a BigInt, which limits
+// BigInts to maxBigIntDigits digits.
+const (
+	bigIntLenDigits = 6
+	maxBigIntDigits = 999999
+)
+
+// BigInt is an integer of any size, encoded so that comparing BigInts as
+// strings compares the integers. This makes it a cmp.Ordered type that
+// DoubleStack and the solvers can sort without rounding, unlike float64
+// beyond 2^53.
+//
+// The encoding is a sign byte ('0' for negative, '1' otherwise), the number
+// of digits padded to bigIntLenDigits and the digits. For negative numbers
+// the digit count and the digits are complemented to 9, so that larger
+// magnitudes sort first.
+type BigInt string
+
+// complement replaces every digit d by 9-d.
+func complement(digits string) string {
+	var b strings.Builder
+	b.Grow(len(digits))
+
+	for i := range len(digits) {
+		b.WriteByte('9' - digits[i] + '0')
+	}
+
+	return b.String()
+}
+
+// NewBigInt encodes n.
+func NewBigInt(n *big.Int) (BigInt, error) {
+	digits := n.Text(10)
+	negative := n.Sign() < 0
+	if negative {
+		digits = digits[1:]
+	}
+
+	if len(digits) > maxBigIntDigits {
+		return "", fmt.Errorf("more than %d digits", maxBigIntDigits)
+	}
+
+	length := fmt.Sprintf("%0*d", bigIntLenDigits, len(digits))
+	if negative {
+		return BigInt("0" + complement(length) + complement(digits)), nil
+	}
+
+	return BigInt("1" + length + digits), nil
+}
+
+// ParseBigInt parses a decimal integer with an optional sign.
+func ParseBigInt(s string) (BigInt, error) {
+	n, ok := new(big.Int).SetString(s, 10)
+	if !ok {
+		return "", fmt.Errorf("not an integer")
+	}
+
+	return NewBigInt(n)
+}
+
+// digits returns the sign and decimal digits of the integer.
+func (b BigInt) digits() (negative bool, digits string) {
+	if len(b) <= bigIntLenDigits {
+		return false, "0"
+	}
+
+	digits = string(b[1+bigIntLenDigits:])
+	if b[0] == '0' {
+		return true, complement(digits)
+	}
+
+	return false, digits
+}
+
+// String returns the integer in decimal.
+func (b BigInt) String() string {
+	negative, digits := b.digits()
+	if negative {
+		return "-" + digits
+	}
+
+	return digits
+}
+
+// Int returns the integer as a *big.Int.
+func (b BigInt) Int() *big.Int {
+	n, _ := new(big.Int).SetString(b.String(), 10)
+	return n
+}
+
+// MarshalText writes the integer in decimal, so that JSON holds the digits
+// rather than the encoding.
+func (b BigInt) MarshalText() ([]byte, error) {
+	return []byte(b.String()), nil
+}
+
+// UnmarshalText parses an integer in decimal.
+func (b *BigInt) UnmarshalText(text []byte) error {
+	n, err := ParseBigInt(string(text))
+	if err != nil {
+		return fmt.Errorf("parsing %q: %v", text, err)
+	}
+
+	*b = n
+	return nil
+}
+
+// ParseBigIntSlice parses decimal integers of any size. The first token
+// that is not an integer or, unless allowDups is set, equals an earlier
+// one is reported as a *ParseError or *DuplicateError.
+func ParseBigIntSlice(numStrings []string, allowDups bool) ([]BigInt, error) {
+	return ParseSlice(numStrings, allowDups, ParseBigInt)
+}
@@ -0,0 +1,133 @@
+package pushswap
+
+import (
+	"encoding/json"
+	"errors"
+	"math/big"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func TestBigIntOrder(t *testing.T) {
+	// Sorted, including values that collide or sort wrongly as float64.
+	values := []string{
+		"-1000000000000000000000",
+		"-9007199254740993",
+		"-9007199254740992",
+		"-100",
+		"-99",
+		"-1",
+		"0",
+		"1",
+		"9",
+		"10",
+		"9007199254740992",
+		"9007199254740993",
+		"18446744073709551615",
+		"18446744073709551616",
+	}
+
+	nums := make([]BigInt, len(values))
+	for i, val := range values {
+		n, err := ParseBigInt(val)
+		if err != nil {
+			t.Fatalf("ParseBigInt(%q) error: %v", val, err)
+		}
+
+		if n.String() != val {
+			t.Errorf("ParseBigInt(%q).String() = %q", val, n.String())
+		}
+
+		nums[i] = n
+	}
+
+	if !slices.IsSorted(nums) {
+		t.Errorf("BigInts do not sort like the integers: %v", nums)
+	}
+
+	shuffled := slices.Clone(nums)
+	rand.New(rand.NewSource(1)).Shuffle(len(shuffled), func(i, j int) {
+		shuffled[i], shuffled[j] = shuffled[j], shuffled[i]
+	})
+
+	if !Verify(shuffled, TurkAlgorithm(shuffled)) {
+		t.Errorf("TurkAlgorithm does not sort %v", shuffled)
+	}
+}
+
+func TestParseBigInt(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{input: "+42", want: "42"},
+		{input: "-0", want: "0"},
+		{input: "007", want: "7"},
+		{input: "-0012", want: "-12"},
+		{input: "1.5", wantErr: true},
+		{input: "1e3", wantErr: true},
+		{input: "0x10", wantErr: true},
+		{input: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseBigInt(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseBigInt(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+
+			if err == nil && got.String() != tt.want {
+				t.Errorf("ParseBigInt(%q) = %s, want %s", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBigIntConversions(t *testing.T) {
+	n, _ := new(big.Int).SetString("-123456789012345678901234567890", 10)
+
+	b, err := NewBigInt(n)
+	if err != nil {
+		t.Fatalf("NewBigInt error: %v", err)
+	}
+
+	if b.Int().Cmp(n) != 0 {
+		t.Errorf("Int() = %v, want %v", b.Int(), n)
+	}
+
+	data, err := json.Marshal([]BigInt{b})
+	if err != nil || string(data) != `["-123456789012345678901234567890"]` {
+		t.Errorf("json.Marshal = %s, %v", data, err)
+	}
+
+	var decoded []BigInt
+	if err := json.Unmarshal(data, &decoded); err != nil || len(decoded) != 1 || decoded[0] != b {
+		t.Errorf("json.Unmarshal = %v, %v, want [%v]", decoded, err, b)
+	}
+}
+
+func TestParseBigIntSlice(t *testing.T) {
+	got, err := ParseBigIntSlice([]string{"9007199254740993", "9007199254740992"}, false)
+	if err != nil || len(got) != 2 || got[0] <= got[1] {
+		t.Errorf("ParseBigIntSlice = %v, %v, want two distinct integers", got, err)
+	}
+
+	_, err = ParseBigIntSlice([]string{"1", "x"}, false)
+	var parseErr *ParseError
+	if !errors.As(err, &parseErr) || parseErr.Index != 1 || parseErr.Reason != "not an integer" {
+		t.Errorf("error = %v, want a *ParseError for index 1", err)
+	}
+
+	_, err = ParseBigIntSlice([]string{"5", "3", "+05"}, false)
+	var dupErr *DuplicateError
+	if !errors.As(err, &dupErr) || dupErr.FirstIndex != 0 || dupErr.SecondIndex != 2 || dupErr.Token != "+05" {
+		t.Errorf("error = %v, want a *DuplicateError for indices 0 and 2", err)
+	}
+
+	if got, err := ParseBigIntSlice([]string{"5", "5"}, true); err != nil || len(got) != 2 {
+		t.Errorf("ParseBigIntSlice with duplicates = %v, %v", got, err)
+	}
+}
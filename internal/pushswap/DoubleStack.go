@@ -11,6 +11,8 @@ import (
 type DoubleStack[T cmp.Ordered] struct {
 	A stack.Stack[T]
 	B stack.Stack[T]
+
+	observer Observer[T]
 }
 
 // NewDoubleStack initialises a DoubleStack with an optional list of values.
@@ -21,72 +23,102 @@ func NewDoubleStack[T cmp.Ordered](nums ...T) *DoubleStack[T] {
 	}
 }
 
+// Clone returns a copy of the stacks that can be changed independently. The
+// copy has no observer.
+func (ds *DoubleStack[T]) Clone() *DoubleStack[T] {
+	clone := &DoubleStack[T]{
+		A: *stack.NewWithCapacity[T](ds.A.Len() + ds.B.Len()),
+		B: *stack.NewWithCapacity[T](ds.A.Len() + ds.B.Len()),
+	}
+
+	for _, val := range ds.A.All() {
+		clone.A.PushBottom(val)
+	}
+
+	for _, val := range ds.B.All() {
+		clone.B.PushBottom(val)
+	}
+
+	return clone
+}
+
 func (ds *DoubleStack[T]) PushToA() Operation {
+	e := ds.begin(PA)
 	val, success := ds.B.Pop()
 	if !success {
-		return Invalid
+		return ds.end(e, Invalid)
 	}
 
 	ds.A.Push(val)
-	return PA
+	return ds.end(e, PA)
 }
 
 func (ds *DoubleStack[T]) PushToB() Operation {
+	e := ds.begin(PB)
 	val, success := ds.A.Pop()
 	if !success {
-		return Invalid
+		return ds.end(e, Invalid)
 	}
 
 	ds.B.Push(val)
-	return PB
+	return ds.end(e, PB)
 }
 
 func (ds *DoubleStack[T]) SwapA() Operation {
+	e := ds.begin(SA)
 	ds.A.Swap()
-	return SA
+	return ds.end(e, SA)
 }
 
 func (ds *DoubleStack[T]) SwapB() Operation {
+	e := ds.begin(SB)
 	ds.B.Swap()
-	return SB
+	return ds.end(e, SB)
 }
 
 func (ds *DoubleStack[T]) SSwap() Operation {
+	e := ds.begin(SS)
 	ds.A.Swap()
 	ds.B.Swap()
-	return SS
+	return ds.end(e, SS)
 }
 
 func (ds *DoubleStack[T]) RotateA() Operation {
+	e := ds.begin(RA)
 	ds.A.Rotate()
-	return RA
+	return ds.end(e, RA)
 }
 
 func (ds *DoubleStack[T]) RotateB() Operation {
+	e := ds.begin(RB)
 	ds.B.Rotate()
-	return RB
+	return ds.end(e, RB)
 }
 
 func (ds *DoubleStack[T]) RRotate() Operation {
+	e := ds.begin(RR)
 	ds.A.Rotate()
 	ds.B.Rotate()
-	return RR
+	return ds.end(e, RR)
 }
 
 func (ds *DoubleStack[T]) ReverseRotateA() Operation {
+	e := ds.begin(RRA)
 	ds.A.ReverseRotate()
-	return RRA
+	return ds.end(e, RRA)
 }
 
 func (ds *DoubleStack[T]) ReverseRotateB() Operation {
+	e := ds.begin(RRB)
 	ds.B.ReverseRotate()
-	return RRB
+	return ds.end(e, RRB)
 }
 
 func (ds *DoubleStack[T]) RReverseRotate() Operation {
+	e := ds.begin(RRR)
 	ds.A.ReverseRotate()
 	ds.B.ReverseRotate()
-	return RRR
+	return ds.end(e, RRR)
 }
 
 // func (ds *DoubleStack[T]) String() string {
@@ -99,31 +131,40 @@ func (ds *DoubleStack[T]) RReverseRotate() Operation {
 // 	return output.String()
 // }
 
+// ExecuteInstruction applies a single operation to the stacks. It returns the
+// operation applied, or Invalid if the operation is unknown or could not be
+// applied, like pushing from an empty stack.
+func (ds *DoubleStack[T]) ExecuteInstruction(op Operation) Operation {
+	switch op {
+	case PA:
+		return ds.PushToA()
+	case PB:
+		return ds.PushToB()
+	case RA:
+		return ds.RotateA()
+	case RB:
+		return ds.RotateB()
+	case RR:
+		return ds.RRotate()
+	case RRA:
+		return ds.ReverseRotateA()
+	case RRB:
+		return ds.ReverseRotateB()
+	case RRR:
+		return ds.RReverseRotate()
+	case SA:
+		return ds.SwapA()
+	case SB:
+		return ds.SwapB()
+	case SS:
+		return ds.SSwap()
+	}
+
+	return Invalid
+}
+
 func (ds *DoubleStack[T]) ExecuteInstructions(instructions []Operation) {
 	for _, op := range instructions {
-		switch op {
-		case PA:
-			ds.PushToA()
-		case PB:
-			ds.PushToB()
-		case RA:
-			ds.RotateA()
-		case RB:
-			ds.RotateB()
-		case RR:
-			ds.RRotate()
-		case RRA:
-			ds.ReverseRotateA()
-		case RRB:
-			ds.ReverseRotateB()
-		case RRR:
-			ds.RReverseRotate()
-		case SA:
-			ds.SwapA()
-		case SB:
-			ds.SwapB()
-		case SS:
-			ds.SSwap()
-		}
+		ds.ExecuteInstruction(op)
 	}
 }
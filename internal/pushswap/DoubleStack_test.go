@@ -851,3 +851,95 @@ func TestRReverseRotateEquivalentToRRAAndRRB(t *testing.T) {
 		t.Errorf("rrr B = %v, rrb B = %v; want equal", gotB1, gotB2)
 	}
 }
+
+func TestExecuteInstruction(t *testing.T) {
+	tests := []struct {
+		name   string
+		initA  []float64
+		initB  []float64
+		op     Operation
+		wantA  []float64
+		wantB  []float64
+		wantOp Operation
+	}{
+		{
+			name:   "pb moves top of A",
+			initA:  []float64{1, 2},
+			initB:  []float64{},
+			op:     PB,
+			wantA:  []float64{2},
+			wantB:  []float64{1},
+			wantOp: PB,
+		},
+		{
+			name:   "pa from empty B is invalid",
+			initA:  []float64{1, 2},
+			initB:  []float64{},
+			op:     PA,
+			wantA:  []float64{1, 2},
+			wantB:  []float64{},
+			wantOp: Invalid,
+		},
+		{
+			name:   "rrr rotates both stacks",
+			initA:  []float64{1, 2, 3},
+			initB:  []float64{4, 5},
+			op:     RRR,
+			wantA:  []float64{3, 1, 2},
+			wantB:  []float64{5, 4},
+			wantOp: RRR,
+		},
+		{
+			name:   "unknown operation is invalid",
+			initA:  []float64{2, 1},
+			initB:  []float64{},
+			op:     Operation("xx"),
+			wantA:  []float64{2, 1},
+			wantB:  []float64{},
+			wantOp: Invalid,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ds := NewDoubleStack(tt.initA...)
+			for _, v := range tt.initB {
+				ds.B.PushBottom(v)
+			}
+
+			gotOp := ds.ExecuteInstruction(tt.op)
+
+			if gotOp != tt.wantOp {
+				t.Errorf("ExecuteInstruction(%q) = %q, want %q", tt.op, gotOp, tt.wantOp)
+			}
+			if gotA := stackContents(ds, "A"); !slicesEqual(gotA, tt.wantA) {
+				t.Errorf("A = %v, want %v", gotA, tt.wantA)
+			}
+			if gotB := stackContents(ds, "B"); !slicesEqual(gotB, tt.wantB) {
+				t.Errorf("B = %v, want %v", gotB, tt.wantB)
+			}
+		})
+	}
+}
+
+func TestClone(t *testing.T) {
+	ds := NewDoubleStack[float64](1, 2, 3)
+	ds.PushToB()
+
+	clone := ds.Clone()
+	if a := stackContents(clone, "A"); !slicesEqual(a, []float64{2, 3}) {
+		t.Errorf("clone A = %v, want [2 3]", a)
+	}
+	if b := stackContents(clone, "B"); !slicesEqual(b, []float64{1}) {
+		t.Errorf("clone B = %v, want [1]", b)
+	}
+
+	clone.RotateA()
+	clone.PushToA()
+	if a := stackContents(ds, "A"); !slicesEqual(a, []float64{2, 3}) {
+		t.Errorf("original A changed to %v", a)
+	}
+	if b := stackContents(ds, "B"); !slicesEqual(b, []float64{1}) {
+		t.Errorf("original B changed to %v", b)
+	}
+}
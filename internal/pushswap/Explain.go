@@ -0,0 +1,58 @@
+package pushswap
+
+import "cmp"
+
+// Phase names the part of the Turk algorithm a step belongs to.
+type Phase string
+
+const (
+	PhaseInitialPush   Phase = "initial push"
+	PhasePushToB       Phase = "push to B"
+	PhaseSortLast3     Phase = "sort last 3"
+	PhasePushToA       Phase = "push to A"
+	PhaseFinalRotation Phase = "final rotation"
+)
+
+// Route names for the ways of bringing a value and its target to the top.
+const (
+	RouteRotate        = "rotate"
+	RouteReverseRotate = "reverse-rotate"
+	RouteSeparate      = "separate"
+)
+
+// RouteCosts holds the number of instructions each route needs to bring a
+// value and its target to the top of their stacks.
+type RouteCosts struct {
+	Rotate        int `json:"rotate"`         // Both stacks rotated, sharing rr.
+	ReverseRotate int `json:"reverse_rotate"` // Both stacks reverse rotated, sharing rrr.
+	Separate      int `json:"separate"`       // Each stack takes its shortest direction.
+}
+
+// Move explains why a value was pushed: it was the cheapest candidate to
+// bring to the top along with its target in the other stack.
+type Move[T cmp.Ordered] struct {
+	Value       T          `json:"value"`
+	FromIndex   int        `json:"from_index"`   // Index of the value in the stack it is pushed from.
+	TargetIndex int        `json:"target_index"` // Index of the value in the other stack it is pushed onto.
+	Costs       RouteCosts `json:"costs"`
+	Route       string     `json:"route"` // The route taken, the cheapest of Costs.
+}
+
+// Step is a group of instructions the Turk algorithm emitted together.
+type Step[T cmp.Ordered] struct {
+	Phase        Phase       `json:"phase"`
+	Instructions []Operation `json:"instructions"`
+	Move         *Move[T]    `json:"move,omitempty"` // Set for the pushes in PhasePushToB and PhasePushToA.
+}
+
+// routeName returns the name of the route stored in a move candidate.
+func routeName(route Operation) string {
+	switch route {
+	case RR:
+		return RouteRotate
+	case RRR:
+		return RouteReverseRotate
+	}
+
+	return RouteSeparate
+}
@@ -0,0 +1,87 @@
+package pushswap
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func TestExplainTurkAlgorithm(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	inputs := [][]float64{{}, {1, 2, 3}, {2, 1}, {3, 1, 2}, {5, 1, 4, 2, 3}}
+
+	for range 20 {
+		nums := make([]float64, 4+rng.Intn(100))
+		for i, n := range rng.Perm(len(nums)) {
+			nums[i] = float64(n)
+		}
+
+		inputs = append(inputs, nums)
+	}
+
+	for _, nums := range inputs {
+		instructions, steps := ExplainTurkAlgorithm(nums)
+
+		if want := TurkAlgorithm(nums); !slices.Equal(instructions, want) {
+			t.Fatalf("ExplainTurkAlgorithm(%v) instructions differ from TurkAlgorithm", nums)
+		}
+
+		var fromSteps []Operation
+		for _, step := range steps {
+			fromSteps = append(fromSteps, step.Instructions...)
+
+			if len(step.Instructions) == 0 {
+				t.Errorf("%v: empty %q step", nums, step.Phase)
+			}
+
+			if (step.Phase == PhasePushToA || step.Phase == PhasePushToB) != (step.Move != nil) {
+				t.Errorf("%v: %q step has move %+v", nums, step.Phase, step.Move)
+				continue
+			}
+
+			if step.Move == nil {
+				continue
+			}
+
+			// The rotations before the push are as many as the cheapest route costs.
+			costs := step.Move.Costs
+			cost := map[string]int{
+				RouteRotate:        costs.Rotate,
+				RouteReverseRotate: costs.ReverseRotate,
+				RouteSeparate:      costs.Separate,
+			}[step.Move.Route]
+
+			if cost != min(costs.Rotate, costs.ReverseRotate, costs.Separate) {
+				t.Errorf("%v: route %q is not the cheapest of %+v", nums, step.Move.Route, costs)
+			}
+
+			if rotations := len(step.Instructions) - 1; rotations > cost {
+				t.Errorf("%v: %d rotations for a move costing %d", nums, rotations, cost)
+			}
+		}
+
+		if !slices.Equal(fromSteps, instructions) {
+			t.Errorf("%v: steps do not add up to the instructions", nums)
+		}
+	}
+}
+
+func TestExplainTurkAlgorithmMove(t *testing.T) {
+	_, steps := ExplainTurkAlgorithm([]int{4, 2, 5, 1, 3})
+
+	phases := make([]Phase, len(steps))
+	for i, step := range steps {
+		phases[i] = step.Phase
+	}
+
+	want := []Phase{PhaseInitialPush, PhaseSortLast3, PhasePushToA, PhasePushToA, PhaseFinalRotation}
+	if !slices.Equal(phases, want) {
+		t.Fatalf("phases = %v, want %v", phases, want)
+	}
+
+	// After pb pb and sorting, A = [1 3 5] and B = [2 4]: 2 goes above 3.
+	move := steps[2].Move
+	if move.Value != 2 || move.FromIndex != 0 || move.TargetIndex != 1 {
+		t.Errorf("first push to A = %+v, want value 2 from index 0 to index 1", move)
+	}
+}
@@ -0,0 +1,58 @@
+package pushswap
+
+import (
+	"cmp"
+
+	stack "push-swap-go/internal/dllStack"
+)
+
+// isSortedRotation reports whether the stack is in ascending order once rotated.
+func isSortedRotation[T cmp.Ordered](s *stack.Stack[T]) bool {
+	first, _ := s.Index(0)
+	prev := first
+	descents := 0
+
+	for _, val := range s.All() {
+		if val < prev {
+			descents++
+		}
+
+		prev = val
+	}
+
+	// Wrapping from the bottom back to the top is a descent too, unless the
+	// stack is already sorted.
+	if descents == 1 && prev > first {
+		descents++
+	}
+
+	return descents <= 1
+}
+
+// Hint returns the next move the Turk algorithm would make from the current
+// state of the stacks, ending with the push or rotation it leads to. The
+// state does not have to be one the algorithm would reach itself: values
+// are pushed to B while A is not sorted, then pushed back to their place in
+// A and finally A is rotated. Hint returns nil when the stacks are sorted.
+func Hint[T cmp.Ordered](ds *DoubleStack[T]) []Operation {
+	switch {
+	case ds.B.Len() == 0 && isSortedRotation(&ds.A):
+		return rotateMinToTop(&ds.A)
+	case ds.A.Len() == 0:
+		return []Operation{PA}
+	case ds.A.Len() <= 3:
+		// sortLast3 sorts the stack it is given.
+		ops := sortLast3(&ds.Clone().A)
+		if len(ops) > 0 || ds.B.Len() == 0 {
+			return ops
+		}
+	case !isSortedRotation(&ds.A):
+		if ds.B.Len() < 2 {
+			return []Operation{PB}
+		}
+
+		return append(generateInstructions(ds, findCheapestMove(ds, stackB)), PB)
+	}
+
+	return append(generateInstructions(ds, findCheapestMove(ds, stackA)), PA)
+}
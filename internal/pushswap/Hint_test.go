@@ -0,0 +1,98 @@
+package pushswap
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func TestIsSortedRotation(t *testing.T) {
+	tests := []struct {
+		name string
+		vals []float64
+		want bool
+	}{
+		{name: "empty", vals: nil, want: true},
+		{name: "single", vals: []float64{4}, want: true},
+		{name: "sorted", vals: []float64{1, 2, 3, 4}, want: true},
+		{name: "rotated", vals: []float64{3, 4, 1, 2}, want: true},
+		{name: "duplicates", vals: []float64{2, 2, 1, 1}, want: true},
+		{name: "reversed", vals: []float64{3, 2, 1}, want: false},
+		{name: "one swap", vals: []float64{1, 3, 2, 4}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isSortedRotation(makeStack(tt.vals...)); got != tt.want {
+				t.Errorf("isSortedRotation(%v) = %v, want %v", tt.vals, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHint(t *testing.T) {
+	tests := []struct {
+		name  string
+		initA []float64
+		initB []float64
+		want  []Operation
+	}{
+		{name: "sorted", initA: []float64{1, 2, 3}, want: nil},
+		{name: "empty", want: nil},
+		{name: "rotated", initA: []float64{4, 1, 2, 3}, want: []Operation{RA}},
+		{name: "rotated near bottom", initA: []float64{2, 3, 4, 1}, want: []Operation{RRA}},
+		{name: "three values", initA: []float64{2, 1, 3}, want: []Operation{SA}},
+		{name: "first push", initA: []float64{5, 1, 4, 2, 3}, want: []Operation{PB}},
+		{name: "everything in B", initB: []float64{2, 1}, want: []Operation{PA}},
+		{name: "push back", initA: []float64{1, 3}, initB: []float64{2}, want: []Operation{RA, PA}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ds := &DoubleStack[float64]{A: *makeStack(tt.initA...), B: *makeStack(tt.initB...)}
+
+			if got := Hint(ds); !slices.Equal(got, tt.want) {
+				t.Errorf("Hint() = %v, want %v", got, tt.want)
+			}
+
+			if a := stackContents(ds, "A"); !slicesEqual(a, tt.initA) && len(tt.initA) > 0 {
+				t.Errorf("Hint changed A to %v", a)
+			}
+		})
+	}
+}
+
+// Following the hints from any state sorts the stacks.
+func TestHintSorts(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+
+	for trial := range 50 {
+		nums := make([]float64, 1+rng.Intn(60))
+		for i, n := range rng.Perm(len(nums)) {
+			nums[i] = float64(n)
+		}
+
+		ds := NewDoubleStack(nums...)
+		// Start from a state the algorithm does not reach by itself.
+		for range rng.Intn(len(nums) + 1) {
+			ds.PushToB()
+		}
+
+		for moves := 0; ; moves++ {
+			if moves > 10*len(nums) {
+				t.Fatalf("trial %d: hints do not sort %v", trial, nums)
+			}
+
+			ops := Hint(ds)
+			if ops == nil {
+				break
+			}
+
+			ds.ExecuteInstructions(ops)
+		}
+
+		if !ds.Holds(slices.Sorted(slices.Values(nums))) {
+			t.Errorf("trial %d: hints left A = %v, B = %v", trial, stackContents(ds, "A"), stackContents(ds, "B"))
+		}
+	}
+}
@@ -0,0 +1,244 @@
+package pushswap
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"unicode"
+)
+
+// InputFormat is the format of the numbers read by NewTokenReader.
+type InputFormat string
+
+const (
+	// FormatText is whitespace separated tokens.
+	FormatText InputFormat = "text"
+	// FormatJSON is an array of numbers and strings, like [3, 1, 2].
+	FormatJSON InputFormat = "json"
+	// FormatCSV is comma separated fields, one or more per line.
+	FormatCSV InputFormat = "csv"
+)
+
+// InputFormats lists the valid formats.
+var InputFormats = []InputFormat{FormatText, FormatJSON, FormatCSV}
+
+// String is required by the flag.Value interface.
+func (f *InputFormat) String() string {
+	return string(*f)
+}
+
+// custom parsing logic for `InputFormat`.
+func (f *InputFormat) Set(value string) error {
+	switch format := InputFormat(value); format {
+	case FormatText, FormatJSON, FormatCSV:
+		*f = format
+		return nil
+	}
+
+	return fmt.Errorf("unknown input format %q, want text, json or csv", value)
+}
+
+// FormatOf picks the format of a file from its extension, defaulting to
+// FormatText.
+func FormatOf(file string) InputFormat {
+	switch strings.ToLower(filepath.Ext(file)) {
+	case ".json":
+		return FormatJSON
+	case ".csv":
+		return FormatCSV
+	}
+
+	return FormatText
+}
+
+// NewTokenReader returns a TokenReader for the tokens of r in the format.
+// The empty format is FormatText.
+func NewTokenReader(r io.Reader, format InputFormat) (TokenReader, error) {
+	switch format {
+	case "", FormatText:
+		return NewTokenizer(r), nil
+	case FormatJSON:
+		return &jsonTokens{reader: newRuneReader(r)}, nil
+	case FormatCSV:
+		reader := csv.NewReader(r)
+		reader.FieldsPerRecord = -1
+		reader.TrimLeadingSpace = true
+		reader.ReuseRecord = true
+
+		return &csvTokens{reader: reader}, nil
+	}
+
+	return nil, fmt.Errorf("unknown input format %q", format)
+}
+
+// jsonTokens reads the elements of a JSON array of numbers and strings. The
+// tokens of numbers are the numbers as written, so that no precision is lost
+// before they are parsed, and those of strings are the unquoted strings.
+type jsonTokens struct {
+	reader  *runeReader
+	index   int  // Index of the next element.
+	started bool // Whether the opening bracket was read.
+	done    bool // Whether the closing bracket was read.
+}
+
+// jsonError reports invalid JSON at a position.
+func jsonError(pos Position, format string, args ...any) error {
+	return fmt.Errorf("invalid JSON at %v: %s", pos, fmt.Sprintf(format, args...))
+}
+
+func (j *jsonTokens) Next() (Token, error) {
+	if j.done {
+		return Token{}, io.EOF
+	}
+
+	c, pos, err := j.reader.skipSpace()
+	if err != nil && err != io.EOF {
+		return Token{}, err
+	}
+
+	switch {
+	case !j.started:
+		if err == io.EOF || c != '[' {
+			return Token{}, jsonError(pos, "expected '['")
+		}
+
+		j.started = true
+
+		c, pos, err = j.reader.skipSpace()
+		if err == nil && c == ']' {
+			return Token{}, j.end()
+		}
+	case err == io.EOF || c != ',' && c != ']':
+		return Token{}, jsonError(pos, "expected ',' or ']'")
+	case c == ']':
+		return Token{}, j.end()
+	default:
+		c, pos, err = j.reader.skipSpace()
+	}
+
+	if err == io.EOF {
+		return Token{}, jsonError(pos, "unexpected end of input")
+	} else if err != nil {
+		return Token{}, err
+	}
+
+	text, err := j.value(c, pos)
+	if err != nil {
+		return Token{}, err
+	}
+
+	tok := Token{Text: text, Index: j.index, Position: pos}
+	j.index++
+
+	return tok, nil
+}
+
+// value reads the rest of the number or string starting with c.
+func (j *jsonTokens) value(c rune, pos Position) (string, error) {
+	var raw strings.Builder
+	raw.WriteRune(c)
+
+	if c == '"' {
+		escaped := false
+		for {
+			next, _, err := j.reader.readRune()
+			if err == io.EOF {
+				return "", jsonError(pos, "unterminated string")
+			} else if err != nil {
+				return "", err
+			}
+
+			raw.WriteRune(next)
+			if next == '"' && !escaped {
+				break
+			}
+
+			escaped = next == '\\' && !escaped
+		}
+
+		var text string
+		if json.Unmarshal([]byte(raw.String()), &text) != nil {
+			return "", jsonError(pos, "invalid string %s", raw.String())
+		}
+
+		return text, nil
+	}
+
+	for {
+		next, _, err := j.reader.readRune()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return "", err
+		}
+
+		if unicode.IsSpace(next) || next == ',' || next == ']' {
+			j.reader.unreadRune()
+			break
+		}
+
+		raw.WriteRune(next)
+	}
+
+	number := raw.String()
+	if c != '-' && (c < '0' || c > '9') || !json.Valid([]byte(number)) {
+		return "", jsonError(pos, "expected a number or a string, got %s", number)
+	}
+
+	return number, nil
+}
+
+// end checks that nothing but whitespace follows the closing bracket.
+func (j *jsonTokens) end() error {
+	j.done = true
+
+	c, pos, err := j.reader.skipSpace()
+	if err == nil {
+		return jsonError(pos, "unexpected %q after the array", c)
+	} else if err != io.EOF {
+		return err
+	}
+
+	return io.EOF
+}
+
+// csvTokens reads the fields of CSV records, so that a file can hold a
+// column or a row of values. Blank fields are skipped. Columns count bytes
+// rather than runes, as encoding/csv does.
+type csvTokens struct {
+	reader *csv.Reader
+	record []string
+	field  int // Index of the next field in record.
+	index  int // Index of the next token.
+}
+
+func (c *csvTokens) Next() (Token, error) {
+	for {
+		for c.field < len(c.record) {
+			i := c.field
+			c.field++
+
+			text := strings.TrimSpace(c.record[i])
+			if text == "" {
+				continue
+			}
+
+			line, column := c.reader.FieldPos(i)
+			tok := Token{Text: text, Index: c.index, Position: Position{Line: line, Column: column}}
+			c.index++
+
+			return tok, nil
+		}
+
+		record, err := c.reader.Read()
+		if err != nil {
+			return Token{}, err
+		}
+
+		c.record = record
+		c.field = 0
+	}
+}
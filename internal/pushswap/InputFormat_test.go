@@ -0,0 +1,171 @@
+package pushswap
+
+import (
+	"errors"
+	"io"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// readTokens reads all tokens of the input in the format.
+func readTokens(input string, format InputFormat) ([]Token, error) {
+	tokens, err := NewTokenReader(strings.NewReader(input), format)
+	if err != nil {
+		return nil, err
+	}
+
+	var all []Token
+	for {
+		tok, err := tokens.Next()
+		if err == io.EOF {
+			return all, nil
+		} else if err != nil {
+			return all, err
+		}
+
+		all = append(all, tok)
+	}
+}
+
+func TestNewTokenReader(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		format  InputFormat
+		want    []Token
+		wantErr string
+	}{
+		{
+			name:   "text",
+			input:  "3 1\n2",
+			format: FormatText,
+			want: []Token{
+				{Text: "3", Index: 0, Position: Position{Line: 1, Column: 1}},
+				{Text: "1", Index: 1, Position: Position{Line: 1, Column: 3}},
+				{Text: "2", Index: 2, Position: Position{Line: 2, Column: 1}},
+			},
+		},
+		{name: "empty json array", input: " [ ]\n", format: FormatJSON},
+		{
+			name:   "json numbers",
+			input:  "[3, -1.5e2,\n  12345678901234567890]",
+			format: FormatJSON,
+			want: []Token{
+				{Text: "3", Index: 0, Position: Position{Line: 1, Column: 2}},
+				{Text: "-1.5e2", Index: 1, Position: Position{Line: 1, Column: 5}},
+				{Text: "12345678901234567890", Index: 2, Position: Position{Line: 2, Column: 3}},
+			},
+		},
+		{
+			name:   "json strings",
+			input:  `["1.2.3","a\"b, c]"]`,
+			format: FormatJSON,
+			want: []Token{
+				{Text: "1.2.3", Index: 0, Position: Position{Line: 1, Column: 2}},
+				{Text: `a"b, c]`, Index: 1, Position: Position{Line: 1, Column: 10}},
+			},
+		},
+		{name: "json not an array", input: `{"a": 1}`, format: FormatJSON, wantErr: "invalid JSON at line 1, column 1: expected '['"},
+		{name: "empty json", input: "", format: FormatJSON, wantErr: "invalid JSON at line 1, column 1: expected '['"},
+		{name: "json missing comma", input: "[1 2]", format: FormatJSON, wantErr: "invalid JSON at line 1, column 4: expected ',' or ']'"},
+		{name: "json trailing comma", input: "[1,]", format: FormatJSON, wantErr: "invalid JSON at line 1, column 4: expected a number or a string, got ]"},
+		{name: "json null", input: "[null]", format: FormatJSON, wantErr: "invalid JSON at line 1, column 2: expected a number or a string, got null"},
+		{name: "json invalid number", input: "[01]", format: FormatJSON, wantErr: "invalid JSON at line 1, column 2: expected a number or a string, got 01"},
+		{name: "json unterminated", input: "[1, 2", format: FormatJSON, wantErr: "invalid JSON at line 1, column 6: expected ',' or ']'"},
+		{name: "json after the array", input: "[1] 2", format: FormatJSON, wantErr: "invalid JSON at line 1, column 5: unexpected '2' after the array"},
+		{
+			name:   "csv column",
+			input:  "3\n 1\n\n2\n",
+			format: FormatCSV,
+			want: []Token{
+				{Text: "3", Index: 0, Position: Position{Line: 1, Column: 1}},
+				{Text: "1", Index: 1, Position: Position{Line: 2, Column: 2}},
+				{Text: "2", Index: 2, Position: Position{Line: 4, Column: 1}},
+			},
+		},
+		{
+			name:   "csv rows",
+			input:  "3,1,\n\"2\",4",
+			format: FormatCSV,
+			want: []Token{
+				{Text: "3", Index: 0, Position: Position{Line: 1, Column: 1}},
+				{Text: "1", Index: 1, Position: Position{Line: 1, Column: 3}},
+				{Text: "2", Index: 2, Position: Position{Line: 2, Column: 1}},
+				{Text: "4", Index: 3, Position: Position{Line: 2, Column: 5}},
+			},
+		},
+		{name: "csv bare quote", input: "1,a\"b", format: FormatCSV, wantErr: `bare " in non-quoted-field`},
+		{name: "unknown format", input: "1", format: "yaml", wantErr: `unknown input format "yaml"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := readTokens(tt.input, tt.format)
+
+			switch {
+			case tt.wantErr != "":
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("error = %v, want %q", err, tt.wantErr)
+				}
+			case err != nil:
+				t.Errorf("unexpected error: %v", err)
+			case !slices.Equal(got, tt.want):
+				t.Errorf("tokens = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatOf(t *testing.T) {
+	tests := map[string]InputFormat{
+		"nums.json":     FormatJSON,
+		"dir/NUMS.JSON": FormatJSON,
+		"nums.csv":      FormatCSV,
+		"nums.nums":     FormatText,
+		"nums":          FormatText,
+		"-":             FormatText,
+	}
+
+	for file, want := range tests {
+		if got := FormatOf(file); got != want {
+			t.Errorf("FormatOf(%q) = %q, want %q", file, got, want)
+		}
+	}
+}
+
+func TestInputFormatSet(t *testing.T) {
+	var f InputFormat
+	for _, format := range InputFormats {
+		if err := f.Set(string(format)); err != nil || f != format {
+			t.Errorf("Set(%q) = %v, format %q", format, err, f)
+		}
+	}
+
+	if err := f.Set("yaml"); err == nil {
+		t.Errorf("Set(\"yaml\") succeeded, want an error")
+	}
+}
+
+func TestReadNumbersFrom(t *testing.T) {
+	tokens, err := NewTokenReader(strings.NewReader("[3, 1, 2]"), FormatJSON)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got, err := ReadNumbersFrom(tokens, ParseOptions{})
+	if err != nil || !slices.Equal(got, []float64{3, 1, 2}) {
+		t.Errorf("ReadNumbersFrom() = %v, %v", got, err)
+	}
+
+	tokens, err = NewTokenReader(strings.NewReader("1\n2\n1.0\n"), FormatCSV)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	_, err = ReadNumbersFrom(tokens, ParseOptions{})
+	var dupErr *DuplicateError
+	if !errors.As(err, &dupErr) || dupErr.Position != (Position{Line: 3, Column: 1}) {
+		t.Errorf("error = %v, want a *DuplicateError at line 3", err)
+	}
+}
@@ -0,0 +1,146 @@
+package pushswap
+
+import (
+	"cmp"
+
+	stack "push-swap-go/internal/dllStack"
+)
+
+// Lengths holds the number of values in each stack.
+type Lengths struct {
+	A, B int
+}
+
+// Event describes an operation executed on a DoubleStack.
+type Event[T cmp.Ordered] struct {
+	Op      Operation // The operation requested.
+	Applied bool      // False if the operation could not be applied, such as a push from an empty stack.
+	Before  Lengths
+	After   Lengths
+	// Moved holds the values that changed position, those of A first: the
+	// value pushed, the two swapped values or the value rotated to the
+	// other end of its stack.
+	Moved []T
+}
+
+// Observer is notified after every operation executed on a DoubleStack.
+type Observer[T cmp.Ordered] interface {
+	Observe(Event[T])
+}
+
+// ObserverFunc adapts a function to the Observer interface.
+type ObserverFunc[T cmp.Ordered] func(Event[T])
+
+func (f ObserverFunc[T]) Observe(e Event[T]) {
+	f(e)
+}
+
+// SetObserver attaches an observer to the stacks, replacing the previous
+// one. A nil observer detaches it; the operations then cost nothing extra.
+func (ds *DoubleStack[T]) SetObserver(o Observer[T]) {
+	ds.observer = o
+}
+
+func (ds *DoubleStack[T]) lengths() Lengths {
+	return Lengths{A: ds.A.Len(), B: ds.B.Len()}
+}
+
+// begin records the state before an operation, or returns nil when there is
+// no observer. It is small enough to be inlined into every operation.
+func (ds *DoubleStack[T]) begin(op Operation) *Event[T] {
+	if ds.observer == nil {
+		return nil
+	}
+
+	return ds.newEvent(op)
+}
+
+func (ds *DoubleStack[T]) newEvent(op Operation) *Event[T] {
+	return &Event[T]{Op: op, Before: ds.lengths(), Moved: ds.moving(op)}
+}
+
+// moving returns the values the operation is about to move.
+func (ds *DoubleStack[T]) moving(op Operation) []T {
+	switch op {
+	case PA:
+		return pushed(nil, &ds.B)
+	case PB:
+		return pushed(nil, &ds.A)
+	case SA:
+		return swapped(nil, &ds.A)
+	case SB:
+		return swapped(nil, &ds.B)
+	case SS:
+		return swapped(swapped(nil, &ds.A), &ds.B)
+	case RA:
+		return rotated(nil, &ds.A)
+	case RB:
+		return rotated(nil, &ds.B)
+	case RR:
+		return rotated(rotated(nil, &ds.A), &ds.B)
+	}
+
+	// Reverse rotations are recorded by moved once the values are on top,
+	// instead of walking to the bottom of the stacks.
+	return nil
+}
+
+// moved completes the values moved by reverse rotations.
+func (ds *DoubleStack[T]) moved(e *Event[T]) {
+	switch e.Op {
+	case RRA:
+		e.Moved = rotated(e.Moved, &ds.A)
+	case RRB:
+		e.Moved = rotated(e.Moved, &ds.B)
+	case RRR:
+		e.Moved = rotated(rotated(e.Moved, &ds.A), &ds.B)
+	}
+}
+
+// pushed appends the value a push takes from the stack.
+func pushed[T cmp.Ordered](moved []T, s *stack.Stack[T]) []T {
+	if val, ok := s.Index(0); ok {
+		moved = append(moved, val)
+	}
+
+	return moved
+}
+
+// swapped appends the top two values of the stack, which a swap exchanges.
+func swapped[T cmp.Ordered](moved []T, s *stack.Stack[T]) []T {
+	if s.Len() < 2 {
+		return moved
+	}
+
+	first, _ := s.Index(0)
+	second, _ := s.Index(1)
+	return append(moved, first, second)
+}
+
+// rotated appends the value at the top of the stack if a rotation moves it,
+// which is the value a rotation takes to the bottom or, once done, the one a
+// reverse rotation brought from the bottom.
+func rotated[T cmp.Ordered](moved []T, s *stack.Stack[T]) []T {
+	if s.Len() < 2 {
+		return moved
+	}
+
+	return pushed(moved, s)
+}
+
+// end reports a completed operation to the observer, if any, and returns its
+// result. Like begin, it is inlined into every operation.
+func (ds *DoubleStack[T]) end(e *Event[T], result Operation) Operation {
+	if e != nil {
+		ds.report(e, result)
+	}
+
+	return result
+}
+
+func (ds *DoubleStack[T]) report(e *Event[T], result Operation) {
+	e.Applied = result != Invalid
+	e.After = ds.lengths()
+	ds.moved(e)
+	ds.observer.Observe(*e)
+}
@@ -0,0 +1,79 @@
+package pushswap
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestObserver(t *testing.T) {
+	tests := []struct {
+		name  string
+		initA []float64
+		initB []float64
+		op    Operation
+		want  Event[float64]
+	}{
+		{name: "pa", initA: []float64{1}, initB: []float64{2, 3}, op: PA,
+			want: Event[float64]{Op: PA, Applied: true, Before: Lengths{1, 2}, After: Lengths{2, 1}, Moved: []float64{2}}},
+		{name: "pa from empty B", initA: []float64{1}, op: PA,
+			want: Event[float64]{Op: PA, Applied: false, Before: Lengths{1, 0}, After: Lengths{1, 0}}},
+		{name: "pb", initA: []float64{1, 4}, op: PB,
+			want: Event[float64]{Op: PB, Applied: true, Before: Lengths{2, 0}, After: Lengths{1, 1}, Moved: []float64{1}}},
+		{name: "sa", initA: []float64{1, 4, 5}, op: SA,
+			want: Event[float64]{Op: SA, Applied: true, Before: Lengths{3, 0}, After: Lengths{3, 0}, Moved: []float64{1, 4}}},
+		{name: "sb with one value", initA: []float64{1}, initB: []float64{2}, op: SB,
+			want: Event[float64]{Op: SB, Applied: true, Before: Lengths{1, 1}, After: Lengths{1, 1}}},
+		{name: "ss", initA: []float64{1, 4}, initB: []float64{2, 3}, op: SS,
+			want: Event[float64]{Op: SS, Applied: true, Before: Lengths{2, 2}, After: Lengths{2, 2}, Moved: []float64{1, 4, 2, 3}}},
+		{name: "ra", initA: []float64{1, 4, 5}, op: RA,
+			want: Event[float64]{Op: RA, Applied: true, Before: Lengths{3, 0}, After: Lengths{3, 0}, Moved: []float64{1}}},
+		{name: "rr", initA: []float64{1, 4}, initB: []float64{2, 3}, op: RR,
+			want: Event[float64]{Op: RR, Applied: true, Before: Lengths{2, 2}, After: Lengths{2, 2}, Moved: []float64{1, 2}}},
+		{name: "rrb", initA: []float64{1}, initB: []float64{2, 3, 6}, op: RRB,
+			want: Event[float64]{Op: RRB, Applied: true, Before: Lengths{1, 3}, After: Lengths{1, 3}, Moved: []float64{6}}},
+		{name: "rrr with one value in A", initA: []float64{1}, initB: []float64{2, 3}, op: RRR,
+			want: Event[float64]{Op: RRR, Applied: true, Before: Lengths{1, 2}, After: Lengths{1, 2}, Moved: []float64{3}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ds := &DoubleStack[float64]{A: *makeStack(tt.initA...), B: *makeStack(tt.initB...)}
+
+			var events []Event[float64]
+			ds.SetObserver(ObserverFunc[float64](func(e Event[float64]) {
+				events = append(events, e)
+			}))
+
+			ds.ExecuteInstruction(tt.op)
+
+			if len(events) != 1 {
+				t.Fatalf("observed %d events, want 1", len(events))
+			}
+
+			got := events[0]
+			if got.Op != tt.want.Op || got.Applied != tt.want.Applied || got.Before != tt.want.Before ||
+				got.After != tt.want.After || !slices.Equal(got.Moved, tt.want.Moved) {
+				t.Errorf("event = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetObserverNil(t *testing.T) {
+	ds := NewDoubleStack[float64](3, 2, 1)
+
+	count := 0
+	ds.SetObserver(ObserverFunc[float64](func(Event[float64]) { count++ }))
+	ds.ExecuteInstructions([]Operation{PB, PB, RR})
+
+	if clone := ds.Clone(); clone.observer != nil {
+		t.Errorf("clone kept the observer")
+	}
+
+	ds.SetObserver(nil)
+	ds.ExecuteInstructions([]Operation{PA, PA})
+
+	if count != 3 {
+		t.Errorf("observed %d events, want 3", count)
+	}
+}
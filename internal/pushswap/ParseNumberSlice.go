@@ -1,33 +1,300 @@
 package pushswap
 
 import (
+	"errors"
 	"fmt"
+	"io"
+	"math"
+	"slices"
 	"strconv"
+	"strings"
+	"unicode/utf8"
 )
 
-type empty struct{}
+// NumberMode selects the numbers accepted by ParseNumberSliceWithOptions.
+type NumberMode string
 
-func ParseNumberSlice(numStrings []string, allowDups bool) ([]float64, error) {
-	numList := make([]float64, 0, len(numStrings))
-	numSeen := map[float64]empty{}
+const (
+	// ModeInt32 accepts decimal integers within the 32-bit range, as in the
+	// classic push-swap rules.
+	ModeInt32 NumberMode = "int32"
+	// ModeFinite accepts decimal numbers that are neither infinite nor NaN.
+	ModeFinite NumberMode = "finite"
+	// ModeAny accepts everything strconv.ParseFloat does, including NaN,
+	// infinities and hexadecimal floats.
+	ModeAny NumberMode = "any"
+)
+
+// NumberModes lists the valid modes.
+var NumberModes = []NumberMode{ModeInt32, ModeFinite, ModeAny}
+
+// String is required by the flag.Value interface.
+func (m *NumberMode) String() string {
+	return string(*m)
+}
+
+// custom parsing logic for `NumberMode`.
+func (m *NumberMode) Set(value string) error {
+	switch mode := NumberMode(value); mode {
+	case ModeInt32, ModeFinite, ModeAny:
+		*m = mode
+		return nil
+	}
+
+	return fmt.Errorf("unknown number mode %q, want int32, finite or any", value)
+}
+
+// Equality decides which numbers ParseNumberSliceWithOptions considers
+// duplicates.
+type Equality string
+
+const (
+	// EqualNumeric treats numbers as duplicates when neither sorts before
+	// the other: -0 equals 0 and every NaN equals every other NaN.
+	EqualNumeric Equality = "numeric"
+	// EqualBitwise treats numbers as duplicates when their float64
+	// representations are identical: -0 differs from 0, and NaNs are equal
+	// only with the same payload.
+	EqualBitwise Equality = "bitwise"
+)
+
+// String is required by the flag.Value interface.
+func (e *Equality) String() string {
+	return string(*e)
+}
 
-	for _, numStr := range numStrings {
+// custom parsing logic for `Equality`.
+func (e *Equality) Set(value string) error {
+	switch equality := Equality(value); equality {
+	case EqualNumeric, EqualBitwise:
+		*e = equality
+		return nil
+	}
+
+	return fmt.Errorf("unknown equality %q, want numeric or bitwise", value)
+}
+
+// key returns a value that is the same for two numbers exactly when they
+// are equal.
+func (e Equality) key(n float64) uint64 {
+	if e != EqualBitwise {
+		if n == 0 {
+			n = 0
+		} else if math.IsNaN(n) {
+			n = math.NaN()
+		}
+	}
+
+	return math.Float64bits(n)
+}
+
+// ParseOptions controls which number lists ParseNumberSliceWithOptions
+// accepts. The zero value only accepts finite numbers without numerically
+// equal duplicates.
+type ParseOptions struct {
+	Mode            NumberMode
+	AllowDuplicates bool
+	Equality        Equality // Ignored when duplicates are allowed.
+}
+
+// ParseError reports a token that is not a number accepted by the mode.
+type ParseError struct {
+	Index    int      // Index of the token in the list.
+	Token    string   // The token as given.
+	Reason   string   // The rule the token broke.
+	Position Position // Only known when the tokens were read from a reader.
+}
+
+func (e *ParseError) Error() string {
+	if e.Position.Line > 0 {
+		return fmt.Sprintf("error parsing %q at %v: %s", e.Token, e.Position, e.Reason)
+	}
+
+	return fmt.Sprintf("error parsing %q: %s", e.Token, e.Reason)
+}
+
+// DuplicateError reports a number that equals an earlier one.
+type DuplicateError struct {
+	FirstIndex  int    // Index of the earlier number.
+	SecondIndex int    // Index of the duplicate.
+	FirstToken  string // The earlier number as given.
+	Token       string // The duplicate as given.
+
+	// Only known when the tokens were read from a reader.
+	FirstPosition Position
+	Position      Position
+}
+
+func (e *DuplicateError) Error() string {
+	if e.Position.Line > 0 {
+		return fmt.Sprintf("duplicate number %q at index %d (%v), first given as %q at index %d (%v)",
+			e.Token, e.SecondIndex, e.Position, e.FirstToken, e.FirstIndex, e.FirstPosition)
+	}
+
+	return fmt.Sprintf("duplicate number %q at index %d, first given as %q at index %d",
+		e.Token, e.SecondIndex, e.FirstToken, e.FirstIndex)
+}
+
+// parseNumber parses a single token following the rules of the mode, which
+// must be valid. It returns the rule broken when the token is not accepted.
+func parseNumber(numStr string, mode NumberMode) (float64, string) {
+	switch mode {
+	case ModeInt32:
+		n, err := strconv.ParseInt(numStr, 10, 32)
+		if err != nil && err.(*strconv.NumError).Err == strconv.ErrRange {
+			return 0, "outside the 32-bit integer range"
+		} else if err != nil {
+			return 0, "not an integer"
+		}
+
+		return float64(n), ""
+	case ModeAny:
 		n, err := strconv.ParseFloat(numStr, 64)
 		if err != nil {
-			return nil, fmt.Errorf("error parsing %q: %v", numStr, err)
+			return 0, err.Error()
+		}
+
+		return n, ""
+	}
+
+	// Hexadecimal floats are the only form with an 'x' that ParseFloat
+	// accepts, and infinities and NaN are the only ones with an 'n'.
+	if strings.ContainsAny(numStr, "xXnN") {
+		return 0, "not a finite decimal number"
+	}
+
+	n, err := strconv.ParseFloat(numStr, 64)
+	if err != nil && err.(*strconv.NumError).Err == strconv.ErrRange && math.IsInf(n, 0) {
+		return 0, "too large to be finite"
+	} else if err != nil {
+		return 0, err.Error()
+	}
+
+	return n, ""
+}
+
+// ParseNumberSlice parses any number strconv.ParseFloat accepts. Use
+// ParseNumberSliceWithOptions to restrict the numbers.
+func ParseNumberSlice(numStrings []string, allowDups bool) ([]float64, error) {
+	return ParseNumberSliceWithOptions(numStrings, ParseOptions{Mode: ModeAny, AllowDuplicates: allowDups})
+}
+
+// ParseNumberSliceWithOptions parses the numbers. The first number the
+// options do not accept is reported as a *ParseError or *DuplicateError.
+func ParseNumberSliceWithOptions(numStrings []string, opts ParseOptions) ([]float64, error) {
+	p, err := numberParser(opts, len(numStrings))
+	if err != nil {
+		return nil, err
+	}
+
+	return p.parseAll(numStrings)
+}
+
+// ReadNumbers is like ParseNumberSliceWithOptions but parses the numbers
+// while reading the whitespace separated tokens from r, and reports the
+// position of the offending tokens in the errors.
+func ReadNumbers(r io.Reader, opts ParseOptions) ([]float64, error) {
+	return ReadNumbersFrom(NewTokenizer(r), opts)
+}
+
+// ReadNumbersFrom is like ReadNumbers but takes the tokens from a
+// TokenReader, for inputs in other formats.
+func ReadNumbersFrom(tokens TokenReader, opts ParseOptions) ([]float64, error) {
+	p, err := numberParser(opts, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	return p.read(tokens)
+}
+
+// numberParser returns a sliceParser for the numbers the options accept.
+func numberParser(opts ParseOptions, size int) (*sliceParser[float64, uint64], error) {
+	switch opts.Mode {
+	case "", ModeInt32, ModeFinite, ModeAny:
+	default:
+		return nil, fmt.Errorf("unknown number mode %q", opts.Mode)
+	}
+
+	switch opts.Equality {
+	case "", EqualNumeric, EqualBitwise:
+	default:
+		if !opts.AllowDuplicates {
+			return nil, fmt.Errorf("unknown equality %q", opts.Equality)
+		}
+	}
+
+	parse := func(numStr string) (float64, error) {
+		n, reason := parseNumber(numStr, opts.Mode)
+		if reason != "" {
+			return 0, errors.New(reason)
 		}
 
-		if !allowDups {
-			_, exists := numSeen[n]
-			if exists {
-				return nil, fmt.Errorf("duplicate number %.f", n)
-			}
+		return n, nil
+	}
+
+	return newSliceParser(size, opts.AllowDuplicates, parse, opts.Equality.key), nil
+}
 
-			numSeen[n] = empty{}
+// highlightContext is the number of tokens Highlight shows on either side
+// of a marked token.
+const highlightContext = 3
+
+// Highlight returns the tokens around the ones a *ParseError or
+// *DuplicateError refers to on one line, with carets under the tokens on a
+// second line. Tokens far from the marked ones are left out. It returns an
+// empty string for other errors.
+func Highlight(numStrings []string, err error) string {
+	var marked []int
+
+	var parseErr *ParseError
+	var dupErr *DuplicateError
+	switch {
+	case errors.As(err, &parseErr):
+		marked = []int{parseErr.Index}
+	case errors.As(err, &dupErr):
+		marked = []int{dupErr.FirstIndex, dupErr.SecondIndex}
+	default:
+		return ""
+	}
+
+	var line, carets strings.Builder
+	write := func(str string, mark bool) {
+		if line.Len() > 0 {
+			line.WriteByte(' ')
+			carets.WriteByte(' ')
 		}
 
-		numList = append(numList, n)
+		line.WriteString(str)
+		caret := " "
+		if mark {
+			caret = "^"
+		}
+		carets.WriteString(strings.Repeat(caret, utf8.RuneCountInString(str)))
+	}
+
+	next := 0 // First token not written yet.
+	for _, index := range marked {
+		if index < 0 || index >= len(numStrings) {
+			return ""
+		}
+
+		from := max(next, index-highlightContext)
+		if from > next {
+			write("...", false)
+		}
+
+		to := min(len(numStrings), index+highlightContext+1)
+		for i := from; i < to; i++ {
+			write(numStrings[i], slices.Contains(marked, i))
+		}
+
+		next = max(next, to)
+	}
+
+	if next < len(numStrings) {
+		write("...", false)
 	}
 
-	return numList, nil
+	return line.String() + "\n" + strings.TrimRight(carets.String(), " ")
 }
@@ -1,6 +1,11 @@
 package pushswap
 
 import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
 	"testing"
 )
 
@@ -111,3 +116,284 @@ func TestParseNumberSlice(t *testing.T) {
 		})
 	}
 }
+
+func TestParseNumberSliceWithOptions(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   []string
+		opts    ParseOptions
+		want    []float64
+		wantErr string
+	}{
+		// --- int32 ---
+		{
+			name:  "int32 range limits",
+			input: []string{"-2147483648", "2147483647", "+5", "007"},
+			opts:  ParseOptions{Mode: ModeInt32},
+			want:  []float64{-2147483648, 2147483647, 5, 7},
+		},
+		{
+			name:    "int32 above range",
+			input:   []string{"1", "2147483648"},
+			opts:    ParseOptions{Mode: ModeInt32},
+			wantErr: `error parsing "2147483648": outside the 32-bit integer range`,
+		},
+		{
+			name:    "int32 below range",
+			input:   []string{"-2147483649"},
+			opts:    ParseOptions{Mode: ModeInt32},
+			wantErr: `error parsing "-2147483649": outside the 32-bit integer range`,
+		},
+		{
+			name:    "int32 rejects floats",
+			input:   []string{"1.5"},
+			opts:    ParseOptions{Mode: ModeInt32},
+			wantErr: `error parsing "1.5": not an integer`,
+		},
+		{
+			name:    "int32 rejects exponents",
+			input:   []string{"1e3"},
+			opts:    ParseOptions{Mode: ModeInt32},
+			wantErr: `error parsing "1e3": not an integer`,
+		},
+		// --- finite ---
+		{
+			name:  "finite floats",
+			input: []string{"1.5", "-2e10", "1e308", "3"},
+			opts:  ParseOptions{Mode: ModeFinite},
+			want:  []float64{1.5, -2e10, 1e308, 3},
+		},
+		{
+			name:    "finite rejects NaN",
+			input:   []string{"1", "NaN"},
+			opts:    ParseOptions{Mode: ModeFinite},
+			wantErr: `error parsing "NaN": not a finite decimal number`,
+		},
+		{
+			name:    "finite rejects infinity",
+			input:   []string{"-Inf"},
+			opts:    ParseOptions{Mode: ModeFinite},
+			wantErr: `error parsing "-Inf": not a finite decimal number`,
+		},
+		{
+			name:    "finite rejects hexadecimal floats",
+			input:   []string{"0x1p-2"},
+			opts:    ParseOptions{Mode: ModeFinite},
+			wantErr: `error parsing "0x1p-2": not a finite decimal number`,
+		},
+		{
+			name:    "finite rejects overflow",
+			input:   []string{"1e400"},
+			opts:    ParseOptions{Mode: ModeFinite},
+			wantErr: `error parsing "1e400": too large to be finite`,
+		},
+		{
+			name:    "zero value is finite",
+			input:   []string{"inf"},
+			wantErr: `error parsing "inf": not a finite decimal number`,
+		},
+		// --- any ---
+		{
+			name:  "any accepts hexadecimal floats and infinities",
+			input: []string{"0x1p-2", "Inf", "-Inf"},
+			opts:  ParseOptions{Mode: ModeAny},
+			want:  []float64{0.25, math.Inf(1), math.Inf(-1)},
+		},
+		// --- options ---
+		{
+			name:  "duplicates allowed",
+			input: []string{"1", "1"},
+			opts:  ParseOptions{Mode: ModeInt32, AllowDuplicates: true},
+			want:  []float64{1, 1},
+		},
+		{
+			name:    "duplicates rejected",
+			input:   []string{"1", "1"},
+			opts:    ParseOptions{Mode: ModeInt32},
+			wantErr: `duplicate number "1" at index 1, first given as "1" at index 0`,
+		},
+		{
+			name:    "duplicates reported exactly",
+			input:   []string{"0.1", "0.4", "0.10"},
+			opts:    ParseOptions{Mode: ModeFinite},
+			wantErr: `duplicate number "0.10" at index 2, first given as "0.1" at index 0`,
+		},
+		// --- equality ---
+		{
+			name:    "numeric zeros are equal",
+			input:   []string{"0", "1", "-0"},
+			wantErr: `duplicate number "-0" at index 2, first given as "0" at index 0`,
+		},
+		{
+			name:  "bitwise zeros differ",
+			input: []string{"0", "1", "-0"},
+			opts:  ParseOptions{Equality: EqualBitwise},
+			want:  []float64{0, 1, math.Copysign(0, -1)},
+		},
+		{
+			name:    "numeric NaNs are equal",
+			input:   []string{"NaN", "1", "nan"},
+			opts:    ParseOptions{Mode: ModeAny},
+			wantErr: `duplicate number "nan" at index 2, first given as "NaN" at index 0`,
+		},
+		{
+			name:    "bitwise NaNs are equal",
+			input:   []string{"NaN", "nan"},
+			opts:    ParseOptions{Mode: ModeAny, Equality: EqualBitwise},
+			wantErr: `duplicate number "nan" at index 1, first given as "NaN" at index 0`,
+		},
+		{
+			name:    "bitwise equal values",
+			input:   []string{"1e2", "100"},
+			opts:    ParseOptions{Equality: EqualBitwise},
+			wantErr: `duplicate number "100" at index 1, first given as "1e2" at index 0`,
+		},
+		{
+			name:    "unknown equality",
+			input:   []string{"1"},
+			opts:    ParseOptions{Equality: "fuzzy"},
+			wantErr: `unknown equality "fuzzy"`,
+		},
+		{
+			name:  "equality ignored with duplicates allowed",
+			input: []string{"1", "1"},
+			opts:  ParseOptions{AllowDuplicates: true, Equality: "fuzzy"},
+			want:  []float64{1, 1},
+		},
+		{
+			name:    "unknown mode",
+			input:   []string{"1"},
+			opts:    ParseOptions{Mode: "decimal"},
+			wantErr: `unknown number mode "decimal"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseNumberSliceWithOptions(tt.input, tt.opts)
+
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("ParseNumberSliceWithOptions() error = %v, want %q", err, tt.wantErr)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("ParseNumberSliceWithOptions() unexpected error: %v", err)
+			}
+
+			// Compare the representations to tell -0 from 0.
+			if !slices.EqualFunc(got, tt.want, func(a, b float64) bool {
+				return math.Float64bits(a) == math.Float64bits(b)
+			}) {
+				t.Errorf("ParseNumberSliceWithOptions() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNumberModeSet(t *testing.T) {
+	for _, mode := range NumberModes {
+		var m NumberMode
+		if err := m.Set(string(mode)); err != nil || m != mode {
+			t.Errorf("Set(%q) = %v, mode %q", mode, err, m)
+		}
+	}
+
+	var m NumberMode
+	if err := m.Set("int64"); err == nil {
+		t.Errorf("Set(\"int64\") succeeded, want an error")
+	}
+}
+
+func TestEqualitySet(t *testing.T) {
+	for _, equality := range []Equality{EqualNumeric, EqualBitwise} {
+		var e Equality
+		if err := e.Set(string(equality)); err != nil || e != equality {
+			t.Errorf("Set(%q) = %v, equality %q", equality, err, e)
+		}
+	}
+
+	var e Equality
+	if err := e.Set("exact"); err == nil {
+		t.Errorf("Set(\"exact\") succeeded, want an error")
+	}
+}
+
+func TestParseNumberSliceErrors(t *testing.T) {
+	_, err := ParseNumberSliceWithOptions([]string{"1", "2", "abc"}, ParseOptions{})
+
+	var parseErr *ParseError
+	if !errors.As(err, &parseErr) {
+		t.Fatalf("error %v is not a *ParseError", err)
+	}
+	if *parseErr != (ParseError{Index: 2, Token: "abc", Reason: `strconv.ParseFloat: parsing "abc": invalid syntax`}) {
+		t.Errorf("ParseError = %+v", *parseErr)
+	}
+
+	_, err = ParseNumberSliceWithOptions([]string{"1", "2", "1.0"}, ParseOptions{})
+
+	var dupErr *DuplicateError
+	if !errors.As(fmt.Errorf("wrapped: %w", err), &dupErr) {
+		t.Fatalf("error %v is not a *DuplicateError", err)
+	}
+	if *dupErr != (DuplicateError{FirstIndex: 0, SecondIndex: 2, FirstToken: "1", Token: "1.0"}) {
+		t.Errorf("DuplicateError = %+v", *dupErr)
+	}
+}
+
+func TestHighlight(t *testing.T) {
+	tokens := strings.Fields("0 1 2 3 4 5 6 7 8 9 10 11 12")
+
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{
+			name: "parse error in the middle",
+			err:  &ParseError{Index: 6, Token: "6"},
+			want: "... 3 4 5 6 7 8 9 ...\n          ^",
+		},
+		{
+			name: "parse error at the start",
+			err:  &ParseError{Index: 0, Token: "0"},
+			want: "0 1 2 3 ...\n^",
+		},
+		{
+			name: "parse error at the end",
+			err:  &ParseError{Index: 12, Token: "12"},
+			want: "... 9 10 11 12\n            ^^",
+		},
+		{
+			name: "duplicates close together",
+			err:  fmt.Errorf("wrapped: %w", &DuplicateError{FirstIndex: 1, SecondIndex: 3}),
+			want: "0 1 2 3 4 5 6 ...\n  ^   ^",
+		},
+		{
+			name: "duplicates far apart",
+			err:  &DuplicateError{FirstIndex: 0, SecondIndex: 11},
+			want: "0 1 2 3 ... 8 9 10 11 12\n^                  ^^",
+		},
+		{
+			name: "index out of range",
+			err:  &ParseError{Index: 13},
+			want: "",
+		},
+		{
+			name: "other error",
+			err:  errors.New("reading file"),
+			want: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Highlight(tokens, tt.err); got != tt.want {
+				t.Errorf("Highlight() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,127 @@
+package pushswap
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// ParseSlice parses every token with parse. The first token parse rejects
+// is reported as a *ParseError with the message of parse's error as the
+// reason and, unless allowDups is set, the first token whose value equals
+// an earlier one as a *DuplicateError.
+func ParseSlice[T comparable](tokens []string, allowDups bool, parse func(string) (T, error)) ([]T, error) {
+	return newSliceParser(len(tokens), allowDups, parse, identity[T]).parseAll(tokens)
+}
+
+// ReadSlice is like ParseSlice but parses the tokens while reading them from
+// r, and reports the position of the offending tokens in the errors.
+func ReadSlice[T comparable](r io.Reader, allowDups bool, parse func(string) (T, error)) ([]T, error) {
+	return ReadSliceFrom(NewTokenizer(r), allowDups, parse)
+}
+
+// ReadSliceFrom is like ReadSlice but takes the tokens from a TokenReader,
+// for inputs in other formats.
+func ReadSliceFrom[T comparable](tokens TokenReader, allowDups bool, parse func(string) (T, error)) ([]T, error) {
+	return newSliceParser(0, allowDups, parse, identity[T]).read(tokens)
+}
+
+func identity[T any](val T) T {
+	return val
+}
+
+// sliceParser collects the values of tokens one at a time. Values with the
+// same key are duplicates.
+type sliceParser[T any, K comparable] struct {
+	allowDups bool
+	parse     func(string) (T, error)
+	key       func(T) K
+	values    []T
+	seen      map[K]Token // First token with the key.
+}
+
+func newSliceParser[T any, K comparable](size int, allowDups bool, parse func(string) (T, error), key func(T) K) *sliceParser[T, K] {
+	return &sliceParser[T, K]{
+		allowDups: allowDups,
+		parse:     parse,
+		key:       key,
+		values:    make([]T, 0, size),
+		seen:      map[K]Token{},
+	}
+}
+
+// add parses a token and appends its value.
+func (p *sliceParser[T, K]) add(tok Token) error {
+	val, err := p.parse(tok.Text)
+	if err != nil {
+		return &ParseError{Index: tok.Index, Token: tok.Text, Reason: err.Error(), Position: tok.Position}
+	}
+
+	if !p.allowDups {
+		key := p.key(val)
+
+		first, exists := p.seen[key]
+		if exists {
+			return &DuplicateError{
+				FirstIndex:    first.Index,
+				SecondIndex:   tok.Index,
+				FirstToken:    first.Text,
+				Token:         tok.Text,
+				FirstPosition: first.Position,
+				Position:      tok.Position,
+			}
+		}
+
+		p.seen[key] = tok
+	}
+
+	p.values = append(p.values, val)
+	return nil
+}
+
+// parseAll parses a list of tokens.
+func (p *sliceParser[T, K]) parseAll(tokens []string) ([]T, error) {
+	for i, token := range tokens {
+		err := p.add(Token{Text: token, Index: i})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return p.values, nil
+}
+
+// read parses the tokens as they are read.
+func (p *sliceParser[T, K]) read(tokens TokenReader) ([]T, error) {
+	for {
+		tok, err := tokens.Next()
+		if err == io.EOF {
+			return p.values, nil
+		} else if err != nil {
+			return nil, fmt.Errorf("reading input: %w", err)
+		}
+
+		err = p.add(tok)
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// ParseInt parses a decimal integer that fits in an int.
+func ParseInt(s string) (int, error) {
+	n, err := strconv.ParseInt(s, 10, 0)
+	if err != nil && err.(*strconv.NumError).Err == strconv.ErrRange {
+		return 0, errors.New("outside the integer range")
+	} else if err != nil {
+		return 0, errors.New("not an integer")
+	}
+
+	return int(n), nil
+}
+
+// ParseString accepts every token as is, for sorting tokens as strings.
+func ParseString(s string) (string, error) {
+	return s, nil
+}
@@ -0,0 +1,73 @@
+package pushswap
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestParseSlice(t *testing.T) {
+	tests := []struct {
+		name      string
+		tokens    []string
+		allowDups bool
+		want      []int
+		wantParse *ParseError
+		wantDup   *DuplicateError
+	}{
+		{name: "empty", tokens: []string{}, want: []int{}},
+		{name: "integers", tokens: []string{"3", "-1", "+2"}, want: []int{3, -1, 2}},
+		{
+			name:      "not an integer",
+			tokens:    []string{"3", "1.5"},
+			wantParse: &ParseError{Index: 1, Token: "1.5", Reason: "not an integer"},
+		},
+		{
+			name:      "outside the range",
+			tokens:    []string{"99999999999999999999"},
+			wantParse: &ParseError{Index: 0, Token: "99999999999999999999", Reason: "outside the integer range"},
+		},
+		{
+			name:    "duplicate value",
+			tokens:  []string{"7", "3", "007"},
+			wantDup: &DuplicateError{FirstIndex: 0, SecondIndex: 2, FirstToken: "7", Token: "007"},
+		},
+		{name: "duplicates allowed", tokens: []string{"7", "7"}, allowDups: true, want: []int{7, 7}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseSlice(tt.tokens, tt.allowDups, ParseInt)
+
+			var parseErr *ParseError
+			var dupErr *DuplicateError
+			switch {
+			case tt.wantParse != nil:
+				if !errors.As(err, &parseErr) || *parseErr != *tt.wantParse {
+					t.Errorf("error = %v, want %+v", err, *tt.wantParse)
+				}
+			case tt.wantDup != nil:
+				if !errors.As(err, &dupErr) || *dupErr != *tt.wantDup {
+					t.Errorf("error = %v, want %+v", err, *tt.wantDup)
+				}
+			case err != nil:
+				t.Errorf("unexpected error: %v", err)
+			case !slices.Equal(got, tt.want):
+				t.Errorf("ParseSlice() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseSliceStrings(t *testing.T) {
+	got, err := ParseSlice([]string{"pear", "Apple", "apple"}, false, ParseString)
+	if err != nil || !slices.Equal(got, []string{"pear", "Apple", "apple"}) {
+		t.Errorf("ParseSlice() = %v, %v", got, err)
+	}
+
+	_, err = ParseSlice([]string{"pear", "fig", "pear"}, false, ParseString)
+	var dupErr *DuplicateError
+	if !errors.As(err, &dupErr) || dupErr.FirstIndex != 0 || dupErr.SecondIndex != 2 {
+		t.Errorf("error = %v, want a *DuplicateError for indices 0 and 2", err)
+	}
+}
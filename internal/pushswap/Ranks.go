@@ -0,0 +1,19 @@
+package pushswap
+
+import (
+	"cmp"
+	"slices"
+)
+
+// Ranks replaces every value by its position among the distinct values in
+// nums, so the smallest value becomes 0. Equal values share a rank.
+func Ranks[T cmp.Ordered](nums []T) []int {
+	sorted := slices.Compact(slices.Sorted(slices.Values(nums)))
+	ranks := make([]int, len(nums))
+
+	for i, val := range nums {
+		ranks[i], _ = slices.BinarySearch(sorted, val)
+	}
+
+	return ranks
+}
@@ -0,0 +1,27 @@
+package pushswap
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestRanks(t *testing.T) {
+	tests := []struct {
+		name string
+		nums []float64
+		want []int
+	}{
+		{name: "empty", nums: []float64{}, want: []int{}},
+		{name: "single", nums: []float64{-7.5}, want: []int{0}},
+		{name: "distinct", nums: []float64{30, -1, 2.5, 100}, want: []int{2, 0, 1, 3}},
+		{name: "duplicates share a rank", nums: []float64{5, 1, 5, 3}, want: []int{2, 0, 2, 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Ranks(tt.nums); !slices.Equal(got, tt.want) {
+				t.Errorf("Ranks(%v) = %v, want %v", tt.nums, got, tt.want)
+			}
+		})
+	}
+}
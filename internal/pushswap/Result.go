@@ -0,0 +1,65 @@
+package pushswap
+
+import (
+	"encoding/json"
+	"math"
+	"strconv"
+	"time"
+)
+
+// JSONValues is a list of values encoded as a JSON array, with NaN and the
+// infinities, which JSON has no numbers for, written as the strings "NaN",
+// "+Inf" and "-Inf". ModeAny accepts them back.
+type JSONValues[T any] []T
+
+func (v JSONValues[T]) MarshalJSON() ([]byte, error) {
+	elems := make([]any, len(v))
+	for i, val := range v {
+		elems[i] = val
+
+		if f, ok := any(val).(float64); ok && (math.IsNaN(f) || math.IsInf(f, 0)) {
+			elems[i] = strconv.FormatFloat(f, 'g', -1, 64)
+		}
+	}
+
+	return json.Marshal(elems)
+}
+
+// Result is a solution as written by push-swap -output-format json and
+// returned by the /solve endpoint of the server. Input holds the numbers
+// that were solved, in the type they were given as.
+type Result[T any] struct {
+	Input        JSONValues[T]     `json:"input"`
+	Algorithm    string            `json:"algorithm"`
+	Instructions []Operation       `json:"instructions"`
+	Count        int               `json:"count"`
+	OpCounts     map[Operation]int `json:"op_counts"`
+	DurationNS   int64             `json:"duration_ns"`
+}
+
+// NewResult returns the result of an algorithm finding the instructions for
+// input in the given duration. Nil slices are replaced with empty ones, so
+// that they are written as [] rather than null.
+func NewResult[T any](input []T, algorithm string, instructions []Operation, duration time.Duration) Result[T] {
+	res := Result[T]{
+		Input:        input,
+		Algorithm:    algorithm,
+		Instructions: instructions,
+		Count:        len(instructions),
+		OpCounts:     map[Operation]int{},
+		DurationNS:   duration.Nanoseconds(),
+	}
+
+	if res.Input == nil {
+		res.Input = []T{}
+	}
+	if res.Instructions == nil {
+		res.Instructions = []Operation{}
+	}
+
+	for _, op := range instructions {
+		res.OpCounts[op]++
+	}
+
+	return res
+}
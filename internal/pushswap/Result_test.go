@@ -0,0 +1,61 @@
+package pushswap
+
+import (
+	"encoding/json"
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewResult(t *testing.T) {
+	res := NewResult([]int{3, 1, 2}, "turk", []Operation{RA, SA, RA}, 5*time.Microsecond)
+
+	if res.Count != 3 || res.OpCounts[RA] != 2 || res.OpCounts[SA] != 1 || res.DurationNS != 5000 {
+		t.Errorf("result = %+v, want 3 instructions, 2 ra, 1 sa and 5000ns", res)
+	}
+
+	data, err := json.Marshal(NewResult[int](nil, "turk", nil, 0))
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+
+	want := `{"input":[],"algorithm":"turk","instructions":[],"count":0,"op_counts":{},"duration_ns":0}`
+	if string(data) != want {
+		t.Errorf("Marshal() = %s, want %s", data, want)
+	}
+}
+
+func TestJSONValues(t *testing.T) {
+	tests := []struct {
+		name   string
+		values any
+		want   string
+	}{
+		{name: "finite floats", values: JSONValues[float64]{1.5, math.Copysign(0, -1), 3}, want: `[1.5,-0,3]`},
+		{name: "non-finite floats", values: JSONValues[float64]{math.NaN(), math.Inf(1), math.Inf(-1)}, want: `["NaN","+Inf","-Inf"]`},
+		{name: "strings", values: JSONValues[string]{"NaN", "b"}, want: `["NaN","b"]`},
+		{name: "nil", values: JSONValues[int](nil), want: `[]`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := json.Marshal(tt.values)
+			if err != nil {
+				t.Fatalf("Marshal() error = %v", err)
+			}
+
+			if string(data) != tt.want {
+				t.Errorf("Marshal() = %s, want %s", data, tt.want)
+			}
+		})
+	}
+
+	// The strings are accepted back in ModeAny.
+	data, _ := json.Marshal(JSONValues[float64]{math.Inf(1), 2})
+	tokens, _ := NewTokenReader(strings.NewReader(string(data)), FormatJSON)
+	nums, err := ReadNumbersFrom(tokens, ParseOptions{Mode: ModeAny})
+	if err != nil || len(nums) != 2 || !math.IsInf(nums[0], 1) {
+		t.Errorf("ReadNumbersFrom(%s) = %v, %v, want [+Inf 2]", data, nums, err)
+	}
+}
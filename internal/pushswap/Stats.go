@@ -0,0 +1,82 @@
+package pushswap
+
+import (
+	"cmp"
+	"slices"
+)
+
+// Phases lists the phases of the Turk algorithm in the order they run.
+var Phases = []Phase{PhaseInitialPush, PhasePushToB, PhaseSortLast3, PhasePushToA, PhaseFinalRotation}
+
+// Histogram counts the moves of every cost.
+type Histogram map[int]int
+
+// Costs returns the costs with at least one move in ascending order.
+func (h Histogram) Costs() []int {
+	costs := make([]int, 0, len(h))
+	for cost := range h {
+		costs = append(costs, cost)
+	}
+
+	slices.Sort(costs)
+	return costs
+}
+
+// PhaseStats holds what one phase of the Turk algorithm spent.
+type PhaseStats struct {
+	Phase        Phase     `json:"phase"`
+	Instructions int       `json:"instructions"`
+	Moves        int       `json:"moves"`           // Values pushed after choosing the cheapest move.
+	Costs        Histogram `json:"costs,omitempty"` // Rotations spent per move, for the push phases.
+}
+
+// TurkStats breaks the instructions of a solution down by phase.
+type TurkStats struct {
+	Instructions int          `json:"instructions"`
+	Phases       []PhaseStats `json:"phases"` // Every phase in the order of Phases.
+}
+
+// Phase returns the statistics of a phase.
+func (s *TurkStats) Phase(phase Phase) *PhaseStats {
+	i := slices.Index(Phases, phase)
+	if i < 0 {
+		return nil
+	}
+
+	return &s.Phases[i]
+}
+
+// StepStats computes the statistics of the steps returned by
+// ExplainTurkAlgorithm.
+func StepStats[T cmp.Ordered](steps []Step[T]) TurkStats {
+	stats := TurkStats{Phases: make([]PhaseStats, len(Phases))}
+	for i, phase := range Phases {
+		stats.Phases[i].Phase = phase
+	}
+
+	for _, step := range steps {
+		phase := stats.Phase(step.Phase)
+		phase.Instructions += len(step.Instructions)
+		stats.Instructions += len(step.Instructions)
+
+		if step.Move == nil {
+			continue
+		}
+
+		if phase.Costs == nil {
+			phase.Costs = Histogram{}
+		}
+
+		phase.Moves++
+		phase.Costs[len(step.Instructions)-1]++
+	}
+
+	return stats
+}
+
+// SolveWithStats returns the same instructions as TurkAlgorithm along with
+// how many of them every phase spent.
+func SolveWithStats[T cmp.Ordered](nums []T) ([]Operation, TurkStats) {
+	instructions, steps := ExplainTurkAlgorithm(nums)
+	return instructions, StepStats(steps)
+}
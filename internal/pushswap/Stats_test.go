@@ -0,0 +1,74 @@
+package pushswap
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func TestSolveWithStats(t *testing.T) {
+	rng := rand.New(rand.NewSource(2))
+	inputs := [][]float64{{}, {1, 2, 3}, {3, 1, 2}, {4, 2, 5, 1, 3}, {4, 1, 3, 2}}
+
+	for range 10 {
+		nums := make([]float64, 4+rng.Intn(200))
+		for i, n := range rng.Perm(len(nums)) {
+			nums[i] = float64(n)
+		}
+
+		inputs = append(inputs, nums)
+	}
+
+	for _, nums := range inputs {
+		instructions, stats := SolveWithStats(nums)
+
+		if !slices.Equal(instructions, TurkAlgorithm(nums)) {
+			t.Fatalf("SolveWithStats(%v) instructions differ from TurkAlgorithm", nums)
+		}
+
+		if stats.Instructions != len(instructions) {
+			t.Errorf("%v: stats count %d instructions, want %d", nums, stats.Instructions, len(instructions))
+		}
+
+		sum := 0
+		for i, phase := range stats.Phases {
+			if phase.Phase != Phases[i] {
+				t.Errorf("%v: phase %d is %q, want %q", nums, i, phase.Phase, Phases[i])
+			}
+
+			// Every move ends with a push after as many rotations as its cost.
+			moveInstructions := 0
+			for cost, moves := range phase.Costs {
+				moveInstructions += (cost + 1) * moves
+			}
+
+			if phase.Costs != nil && moveInstructions != phase.Instructions {
+				t.Errorf("%v: %q costs %v add up to %d instructions, want %d", nums, phase.Phase, phase.Costs, moveInstructions, phase.Instructions)
+			}
+
+			sum += phase.Instructions
+		}
+
+		if sum != stats.Instructions {
+			t.Errorf("%v: phases add up to %d instructions, want %d", nums, sum, stats.Instructions)
+		}
+
+		if n := len(nums); n > 3 && !slices.IsSorted(nums) {
+			// The first two values are pushed to B without a move.
+			if got, want := stats.Phase(PhasePushToB).Moves, max(n-5, 0); got != want {
+				t.Errorf("%v: %d moves to B, want %d", nums, got, want)
+			}
+			if got, want := stats.Phase(PhasePushToA).Moves, max(n-3, 2); got != want {
+				t.Errorf("%v: %d moves to A, want %d", nums, got, want)
+			}
+		}
+	}
+}
+
+func TestHistogramCosts(t *testing.T) {
+	h := Histogram{3: 1, 0: 4, 1: 2}
+
+	if got := h.Costs(); !slices.Equal(got, []int{0, 1, 3}) {
+		t.Errorf("Costs() = %v, want [0 1 3]", got)
+	}
+}
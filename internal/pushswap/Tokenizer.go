@@ -0,0 +1,117 @@
+package pushswap
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+)
+
+// Position locates a token in its input. Line and Column count from 1, with
+// Column counted in runes. The zero Position means the location is unknown.
+type Position struct {
+	Line   int
+	Column int
+}
+
+func (p Position) String() string {
+	return fmt.Sprintf("line %d, column %d", p.Line, p.Column)
+}
+
+// Token is a token read from an input.
+type Token struct {
+	Text     string
+	Index    int // Index of the token in the input.
+	Position Position
+}
+
+// TokenReader reads the tokens of an input one at a time. Next returns
+// io.EOF after the last token.
+type TokenReader interface {
+	Next() (Token, error)
+}
+
+// runeReader reads runes, keeping track of their positions.
+type runeReader struct {
+	reader *bufio.Reader
+	next   Position // Position of the next rune.
+	last   Position // Position of the last rune read, for unreadRune.
+}
+
+func newRuneReader(r io.Reader) *runeReader {
+	return &runeReader{reader: bufio.NewReader(r), next: Position{Line: 1, Column: 1}}
+}
+
+// readRune returns the next rune and its position.
+func (r *runeReader) readRune() (rune, Position, error) {
+	c, _, err := r.reader.ReadRune()
+	if err != nil {
+		return 0, r.next, err
+	}
+
+	r.last = r.next
+	if c == '\n' {
+		r.next.Line++
+		r.next.Column = 1
+	} else {
+		r.next.Column++
+	}
+
+	return c, r.last, nil
+}
+
+// unreadRune puts back the last rune read.
+func (r *runeReader) unreadRune() {
+	r.reader.UnreadRune()
+	r.next = r.last
+}
+
+// skipSpace reads up to the next rune that is not whitespace and returns it
+// with its position.
+func (r *runeReader) skipSpace() (rune, Position, error) {
+	for {
+		c, pos, err := r.readRune()
+		if err != nil || !unicode.IsSpace(c) {
+			return c, pos, err
+		}
+	}
+}
+
+// Tokenizer reads whitespace separated tokens from a reader. Only the token
+// being read is kept in memory, so lines can be arbitrarily long.
+type Tokenizer struct {
+	reader *runeReader
+	index  int // Index of the next token.
+}
+
+// NewTokenizer returns a Tokenizer reading from r.
+func NewTokenizer(r io.Reader) *Tokenizer {
+	return &Tokenizer{reader: newRuneReader(r)}
+}
+
+// Next returns the next token. It returns io.EOF after the last token, and
+// any other error the reader returns as is.
+func (t *Tokenizer) Next() (Token, error) {
+	c, pos, err := t.reader.skipSpace()
+	if err != nil {
+		return Token{}, err
+	}
+
+	var text strings.Builder
+	for {
+		text.WriteRune(c)
+
+		c, _, err = t.reader.readRune()
+		if err == io.EOF || err == nil && unicode.IsSpace(c) {
+			break
+		} else if err != nil {
+			return Token{}, err
+		}
+	}
+
+	tok := Token{Text: text.String(), Index: t.index, Position: pos}
+	t.index++
+
+	return tok, nil
+}
@@ -0,0 +1,148 @@
+package pushswap
+
+import (
+	"errors"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestTokenizer(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []Token
+	}{
+		{name: "empty", input: ""},
+		{name: "blank lines", input: "\n \t\n"},
+		{
+			name:  "one line",
+			input: "3 -1  2",
+			want: []Token{
+				{Text: "3", Index: 0, Position: Position{Line: 1, Column: 1}},
+				{Text: "-1", Index: 1, Position: Position{Line: 1, Column: 3}},
+				{Text: "2", Index: 2, Position: Position{Line: 1, Column: 7}},
+			},
+		},
+		{
+			name:  "several lines",
+			input: "  1\r\n\n2\t3\n",
+			want: []Token{
+				{Text: "1", Index: 0, Position: Position{Line: 1, Column: 3}},
+				{Text: "2", Index: 1, Position: Position{Line: 3, Column: 1}},
+				{Text: "3", Index: 2, Position: Position{Line: 3, Column: 3}},
+			},
+		},
+		{
+			name:  "columns count runes",
+			input: "é 1",
+			want: []Token{
+				{Text: "é", Index: 0, Position: Position{Line: 1, Column: 1}},
+				{Text: "1", Index: 1, Position: Position{Line: 1, Column: 3}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// OneByteReader makes tokens span several reads.
+			tokenizer := NewTokenizer(iotest.OneByteReader(strings.NewReader(tt.input)))
+
+			var got []Token
+			for {
+				tok, err := tokenizer.Next()
+				if err == io.EOF {
+					break
+				} else if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+
+				got = append(got, tok)
+			}
+
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("tokens = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTokenizerReadError(t *testing.T) {
+	tokenizer := NewTokenizer(iotest.TimeoutReader(strings.NewReader("1 2")))
+
+	_, err := tokenizer.Next()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	_, err = tokenizer.Next()
+	if !errors.Is(err, iotest.ErrTimeout) {
+		t.Errorf("error = %v, want %v", err, iotest.ErrTimeout)
+	}
+}
+
+func TestReadSlice(t *testing.T) {
+	// A single line much longer than a bufio.Scanner accepts by default.
+	var input strings.Builder
+	for i := range 50000 {
+		input.WriteString(" ")
+		input.WriteString(strconv.Itoa(i))
+	}
+
+	got, err := ReadSlice(strings.NewReader(input.String()), false, ParseInt)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 50000 || got[0] != 0 || got[49999] != 49999 {
+		t.Errorf("ReadSlice() returned %d values", len(got))
+	}
+
+	_, err = ReadSlice(strings.NewReader("1 2\n3 x"), false, ParseInt)
+	var parseErr *ParseError
+	if !errors.As(err, &parseErr) {
+		t.Fatalf("error %v is not a *ParseError", err)
+	}
+	if *parseErr != (ParseError{Index: 3, Token: "x", Reason: "not an integer", Position: Position{Line: 2, Column: 3}}) {
+		t.Errorf("ParseError = %+v", *parseErr)
+	}
+	if want := `error parsing "x" at line 2, column 3: not an integer`; err.Error() != want {
+		t.Errorf("Error() = %q, want %q", err.Error(), want)
+	}
+
+	_, err = ReadSlice(iotest.TimeoutReader(strings.NewReader("1 2")), false, ParseInt)
+	if !errors.Is(err, iotest.ErrTimeout) {
+		t.Errorf("error = %v, want %v", err, iotest.ErrTimeout)
+	}
+}
+
+func TestReadNumbers(t *testing.T) {
+	got, err := ReadNumbers(strings.NewReader("3 -1.5\n2"), ParseOptions{})
+	if err != nil || !slices.Equal(got, []float64{3, -1.5, 2}) {
+		t.Errorf("ReadNumbers() = %v, %v", got, err)
+	}
+
+	_, err = ReadNumbers(strings.NewReader("1 2\n  1.0"), ParseOptions{})
+	var dupErr *DuplicateError
+	if !errors.As(err, &dupErr) {
+		t.Fatalf("error %v is not a *DuplicateError", err)
+	}
+	want := DuplicateError{
+		FirstIndex:    0,
+		SecondIndex:   2,
+		FirstToken:    "1",
+		Token:         "1.0",
+		FirstPosition: Position{Line: 1, Column: 1},
+		Position:      Position{Line: 2, Column: 3},
+	}
+	if *dupErr != want {
+		t.Errorf("DuplicateError = %+v, want %+v", *dupErr, want)
+	}
+
+	_, err = ReadNumbers(strings.NewReader("1"), ParseOptions{Mode: "decimal"})
+	if err == nil {
+		t.Errorf("ReadNumbers() with an unknown mode succeeded, want an error")
+	}
+}
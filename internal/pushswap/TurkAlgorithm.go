@@ -2,6 +2,7 @@ package pushswap
 
 import (
 	"cmp"
+	"context"
 	"math"
 	"slices"
 
@@ -19,6 +20,7 @@ type moveCandidate struct {
 	fromIdx int
 	toIdx   int
 	cost    int
+	costs   RouteCosts // The costs of every route to the chosen target.
 	target  stackID
 	route   Operation
 }
@@ -275,6 +277,7 @@ func findCheapestTarget[T cmp.Ordered](stacks *DoubleStack[T], fromIdx int, targ
 		if minCost < cheapest.cost {
 			cheapest.toIdx = toIndex
 			cheapest.cost = minCost
+			cheapest.costs = RouteCosts{Rotate: rotateCost, ReverseRotate: reverseRotateCost, Separate: separateCost}
 			switch minCost {
 			case rotateCost:
 				cheapest.route = RR
@@ -377,48 +380,141 @@ func generateInstructions[T cmp.Ordered](stacks *DoubleStack[T], move moveCandid
 	return instructions
 }
 
-func TurkAlgorithm[T cmp.Ordered](nums []T) []Operation {
-	if slices.IsSorted(nums) {
+// rotateMinToTop returns the rotations of stack A bringing its minimum to the top.
+func rotateMinToTop[T cmp.Ordered](sA *stack.Stack[T]) []Operation {
+	minIndices := findMinimums(sA)
+	if len(minIndices) < 1 {
 		return nil
 	}
 
-	stacks := NewDoubleStack(nums...)
+	slices.Sort(minIndices)
+	minIdx := minIndices[0]
 
-	if len(nums) <= 3 {
-		return sortLast3(&stacks.A)
+	if minIdx == 0 {
+		return nil
 	}
 
-	instructions := []Operation{stacks.PushToB(), stacks.PushToB()}
+	if minIdx < sA.Len()-minIdx {
+		return slices.Repeat([]Operation{RA}, minIdx)
+	}
 
-	for stacks.A.Len() > 3 {
-		move := findCheapestMove(stacks, stackB)
-		ops := append(generateInstructions(stacks, move), PB)
+	return slices.Repeat([]Operation{RRA}, sA.Len()-minIdx)
+}
 
-		stacks.ExecuteInstructions(ops)
-		instructions = append(instructions, ops...)
+// turkSolver runs the Turk algorithm, recording the reasoning behind every
+// step when explain is set. It stops before every push once ctx is done.
+type turkSolver[T cmp.Ordered] struct {
+	ctx          context.Context
+	stacks       *DoubleStack[T]
+	instructions []Operation
+	explain      bool
+	steps        []Step[T]
+}
+
+// emit executes the instructions of a step and appends them to the solution.
+func (s *turkSolver[T]) emit(phase Phase, ops []Operation, move *moveCandidate) {
+	if s.explain && len(ops) > 0 {
+		step := Step[T]{Phase: phase, Instructions: ops}
+		if move != nil {
+			step.Move = s.describe(*move)
+		}
+
+		s.steps = append(s.steps, step)
 	}
 
-	instructions = append(instructions, sortLast3(&stacks.A)...)
+	s.stacks.ExecuteInstructions(ops)
+	s.instructions = append(s.instructions, ops...)
+}
 
-	for stacks.B.Len() > 0 {
-		move := findCheapestMove(stacks, stackA)
-		ops := append(generateInstructions(stacks, move), PA)
+// describe explains a move before it is executed.
+func (s *turkSolver[T]) describe(move moveCandidate) *Move[T] {
+	from := &s.stacks.A
+	if move.target == stackA {
+		from = &s.stacks.B
+	}
 
-		stacks.ExecuteInstructions(ops)
-		instructions = append(instructions, ops...)
+	value, _ := from.Index(move.fromIdx)
+	return &Move[T]{
+		Value:       value,
+		FromIndex:   move.fromIdx,
+		TargetIndex: move.toIdx,
+		Costs:       move.costs,
+		Route:       routeName(move.route),
 	}
+}
 
-	// Rotate stack A until minimum comes to the top.
-	minIndices := findMinimums(&stacks.A)
-	slices.Sort(minIndices)
-	minIdx := minIndices[0]
+// pushCheapest moves the cheapest value to its target and pushes it there.
+func (s *turkSolver[T]) pushCheapest(to stackID) {
+	move := findCheapestMove(s.stacks, to)
 
-	var rotations []Operation
-	if minIdx < stacks.A.Len()-minIdx {
-		rotations = slices.Repeat([]Operation{RA}, minIdx)
-	} else {
-		rotations = slices.Repeat([]Operation{RRA}, stacks.A.Len()-minIdx)
+	push, phase := PB, PhasePushToB
+	if to == stackA {
+		push, phase = PA, PhasePushToA
+	}
+
+	s.emit(phase, append(generateInstructions(s.stacks, move), push), &move)
+}
+
+func (s *turkSolver[T]) solve() error {
+	if s.stacks.A.Len() <= 3 {
+		// sortLast3 sorts the stack itself, so it runs on a copy.
+		s.emit(PhaseSortLast3, sortLast3(&s.stacks.Clone().A), nil)
+		return nil
+	}
+
+	s.emit(PhaseInitialPush, []Operation{PB, PB}, nil)
+
+	for s.stacks.A.Len() > 3 {
+		if err := s.ctx.Err(); err != nil {
+			return err
+		}
+
+		s.pushCheapest(stackB)
+	}
+
+	s.emit(PhaseSortLast3, sortLast3(&s.stacks.Clone().A), nil)
+
+	for s.stacks.B.Len() > 0 {
+		if err := s.ctx.Err(); err != nil {
+			return err
+		}
+
+		s.pushCheapest(stackA)
+	}
+
+	s.emit(PhaseFinalRotation, rotateMinToTop(&s.stacks.A), nil)
+	return nil
+}
+
+func TurkAlgorithm[T cmp.Ordered](nums []T) []Operation {
+	instructions, _ := TurkAlgorithmContext(context.Background(), nums)
+	return instructions
+}
+
+// TurkAlgorithmContext is like TurkAlgorithm but gives up with the context's
+// error once it is done, so that abandoned inputs stop costing time.
+func TurkAlgorithmContext[T cmp.Ordered](ctx context.Context, nums []T) ([]Operation, error) {
+	if slices.IsSorted(nums) {
+		return nil, nil
+	}
+
+	s := &turkSolver[T]{ctx: ctx, stacks: NewDoubleStack(nums...)}
+	if err := s.solve(); err != nil {
+		return nil, err
+	}
+
+	return s.instructions, nil
+}
+
+// ExplainTurkAlgorithm returns the same instructions as TurkAlgorithm along
+// with the steps they were emitted in and why every value was pushed where
+// it was.
+func ExplainTurkAlgorithm[T cmp.Ordered](nums []T) ([]Operation, []Step[T]) {
+	if slices.IsSorted(nums) {
+		return nil, nil
 	}
 
-	return append(instructions, rotations...)
+	s := &turkSolver[T]{ctx: context.Background(), stacks: NewDoubleStack(nums...), explain: true}
+	s.solve()
+	return s.instructions, s.steps
 }
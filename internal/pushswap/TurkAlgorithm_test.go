@@ -2,6 +2,7 @@ package pushswap
 
 import (
 	"cmp"
+	"context"
 	"math"
 	"slices"
 	"testing"
@@ -831,6 +832,29 @@ func TestTurkAlgorithmProducesValidOpsOnly(t *testing.T) {
 	}
 }
 
+func TestTurkAlgorithmContext(t *testing.T) {
+	input := []float64{8, 3, 6, 1, 7, 2, 5, 4}
+
+	got, err := TurkAlgorithmContext(context.Background(), input)
+	if err != nil || !slices.Equal(got, TurkAlgorithm(input)) {
+		t.Errorf("TurkAlgorithmContext() = %v, %v, want the instructions of TurkAlgorithm", got, err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	got, err = TurkAlgorithmContext(ctx, input)
+	if err != context.Canceled || got != nil {
+		t.Errorf("TurkAlgorithmContext() with a cancelled context = %v, %v, want %v", got, err, context.Canceled)
+	}
+
+	// Small inputs are sorted without checking the context.
+	got, err = TurkAlgorithmContext(ctx, []float64{2, 1})
+	if err != nil || !slices.Equal(got, []Operation{SA}) {
+		t.Errorf("TurkAlgorithmContext() = %v, %v, want [sa]", got, err)
+	}
+}
+
 // --- TestFindCheapestMove (whitebox) ---
 func TestFindCheapestMove(t *testing.T) {
 	tests := []struct {
@@ -0,0 +1,31 @@
+package pushswap
+
+import (
+	"cmp"
+	"slices"
+)
+
+// Holds reports whether B is empty and A holds exactly the values of
+// reference from top to bottom.
+func (ds *DoubleStack[T]) Holds(reference []T) bool {
+	if ds.B.Len() > 0 || ds.A.Len() != len(reference) {
+		return false
+	}
+
+	for i, val := range ds.A.All() {
+		if val != reference[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Verify reports whether the instructions sort nums in ascending order,
+// leaving every value in A and B empty.
+func Verify[T cmp.Ordered](nums []T, instructions []Operation) bool {
+	ds := NewDoubleStack(nums...)
+
+	ds.ExecuteInstructions(instructions)
+	return ds.Holds(slices.Sorted(slices.Values(nums)))
+}
@@ -0,0 +1,49 @@
+package pushswap
+
+import (
+	"testing"
+)
+
+func TestVerify(t *testing.T) {
+	tests := []struct {
+		name         string
+		nums         []float64
+		instructions []Operation
+		want         bool
+	}{
+		{name: "empty input", nums: []float64{}, instructions: nil, want: true},
+		{name: "already sorted", nums: []float64{1, 2, 3}, instructions: nil, want: true},
+		{name: "sorted by instructions", nums: []float64{2, 1, 3}, instructions: []Operation{SA}, want: true},
+		{name: "sorted with duplicates", nums: []float64{2, 1, 2}, instructions: []Operation{SA}, want: true},
+		{name: "left unsorted", nums: []float64{2, 1, 3}, instructions: []Operation{RA}, want: false},
+		{name: "values left in B", nums: []float64{1, 2, 3}, instructions: []Operation{PB}, want: false},
+		{name: "round trip through B", nums: []float64{2, 1}, instructions: []Operation{PB, PB, PA, PA, SA}, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Verify(tt.nums, tt.instructions); got != tt.want {
+				t.Errorf("Verify(%v, %v) = %v, want %v", tt.nums, tt.instructions, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHolds(t *testing.T) {
+	ds := NewDoubleStack[float64](1, 2, 3)
+
+	if !ds.Holds([]float64{1, 2, 3}) {
+		t.Errorf("Holds() = false for identical contents")
+	}
+	if ds.Holds([]float64{1, 2}) {
+		t.Errorf("Holds() = true for a shorter reference")
+	}
+	if ds.Holds([]float64{1, 3, 2}) {
+		t.Errorf("Holds() = true for a different order")
+	}
+
+	ds.PushToB()
+	if ds.Holds([]float64{2, 3}) {
+		t.Errorf("Holds() = true while B is not empty")
+	}
+}
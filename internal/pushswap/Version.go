@@ -0,0 +1,81 @@
+package pushswap
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// versionLenDigits is the width of the digit count of every component of a
+// Version, which limits components to 99 digits.
+const versionLenDigits = 2
+
+// Version is a version number like 1.2.10, encoded so that comparing
+// Versions as strings compares them component by component: 1.2.9 sorts
+// before 1.2.10, and 1.2 before 1.2.0.
+//
+// Every component is encoded as its number of digits, padded to
+// versionLenDigits, followed by its digits without leading zeros.
+type Version string
+
+// ParseVersion parses dot separated decimal numbers with an optional "v"
+// prefix. The prefix and leading zeros are not kept, so "v1.02" equals
+// "1.2".
+func ParseVersion(s string) (Version, error) {
+	components := strings.Split(strings.TrimPrefix(s, "v"), ".")
+
+	var b strings.Builder
+	for _, component := range components {
+		if component == "" || strings.Trim(component, "0123456789") != "" {
+			return "", errors.New("not a version like 1.2.3")
+		}
+
+		digits := strings.TrimLeft(component, "0")
+		if digits == "" {
+			digits = "0"
+		}
+
+		if len(digits) > 99 {
+			return "", errors.New("version component with more than 99 digits")
+		}
+
+		fmt.Fprintf(&b, "%0*d%s", versionLenDigits, len(digits), digits)
+	}
+
+	return Version(b.String()), nil
+}
+
+// String returns the version with its components separated by dots.
+func (v Version) String() string {
+	var components []string
+
+	for rest := string(v); len(rest) > versionLenDigits; {
+		n, err := strconv.Atoi(rest[:versionLenDigits])
+		if err != nil || len(rest) < versionLenDigits+n {
+			break
+		}
+
+		components = append(components, rest[versionLenDigits:versionLenDigits+n])
+		rest = rest[versionLenDigits+n:]
+	}
+
+	return strings.Join(components, ".")
+}
+
+// MarshalText writes the version with its components separated by dots, so
+// that JSON holds the version rather than the encoding.
+func (v Version) MarshalText() ([]byte, error) {
+	return []byte(v.String()), nil
+}
+
+// UnmarshalText parses a version.
+func (v *Version) UnmarshalText(text []byte) error {
+	version, err := ParseVersion(string(text))
+	if err != nil {
+		return fmt.Errorf("parsing %q: %v", text, err)
+	}
+
+	*v = version
+	return nil
+}
@@ -0,0 +1,79 @@
+package pushswap
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+)
+
+func TestVersionOrder(t *testing.T) {
+	// Sorted, unlike the same strings.
+	sorted := []string{"0.9", "1", "1.0", "1.2", "1.2.0", "1.2.9", "1.2.10", "1.10", "2.0.0", "10.0"}
+
+	versions := make([]Version, len(sorted))
+	for i, s := range sorted {
+		v, err := ParseVersion(s)
+		if err != nil {
+			t.Fatalf("ParseVersion(%q) error: %v", s, err)
+		}
+
+		if v.String() != s {
+			t.Errorf("ParseVersion(%q).String() = %q", s, v.String())
+		}
+
+		versions[i] = v
+	}
+
+	if !slices.IsSorted(versions) {
+		t.Errorf("versions do not sort by component: %v", versions)
+	}
+
+	shuffled := []Version{versions[7], versions[2], versions[9], versions[0], versions[5], versions[6]}
+	if !Verify(shuffled, TurkAlgorithm(shuffled)) {
+		t.Errorf("TurkAlgorithm does not sort %v", shuffled)
+	}
+}
+
+func TestParseVersion(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{input: "v1.2.3", want: "1.2.3"},
+		{input: "01.002", want: "1.2"},
+		{input: "0.0", want: "0.0"},
+		{input: "1..2", wantErr: true},
+		{input: "1.2.", wantErr: true},
+		{input: "1.2-rc1", wantErr: true},
+		{input: "-1", wantErr: true},
+		{input: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseVersion(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseVersion(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+
+			if err == nil && got.String() != tt.want {
+				t.Errorf("ParseVersion(%q) = %s, want %s", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVersionJSON(t *testing.T) {
+	v, _ := ParseVersion("v3.10.1")
+
+	data, err := json.Marshal(v)
+	if err != nil || string(data) != `"3.10.1"` {
+		t.Errorf("json.Marshal = %s, %v", data, err)
+	}
+
+	var decoded Version
+	if err := json.Unmarshal(data, &decoded); err != nil || decoded != v {
+		t.Errorf("json.Unmarshal = %v, %v, want %v", decoded, err, v)
+	}
+}
@@ -16,3 +16,40 @@ const (
 	SB      Operation = "sb"
 	SS      Operation = "ss"
 )
+
+// ParseOperation returns the operation with the given name, or false if there
+// is no such operation.
+func ParseOperation(name string) (Operation, bool) {
+	switch op := Operation(name); op {
+	case PA, PB, RA, RB, RR, RRA, RRB, RRR, SA, SB, SS:
+		return op, true
+	}
+
+	return Invalid, false
+}
+
+// Inverse returns the operation that undoes op. Swaps are their own inverse.
+func (op Operation) Inverse() Operation {
+	switch op {
+	case PA:
+		return PB
+	case PB:
+		return PA
+	case RA:
+		return RRA
+	case RB:
+		return RRB
+	case RR:
+		return RRR
+	case RRA:
+		return RA
+	case RRB:
+		return RB
+	case RRR:
+		return RR
+	case SA, SB, SS:
+		return op
+	}
+
+	return Invalid
+}
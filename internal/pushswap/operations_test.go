@@ -0,0 +1,48 @@
+package pushswap
+
+import (
+	"testing"
+)
+
+var allOperations = []Operation{PA, PB, RA, RB, RR, RRA, RRB, RRR, SA, SB, SS}
+
+func TestParseOperation(t *testing.T) {
+	for _, op := range allOperations {
+		got, ok := ParseOperation(string(op))
+		if !ok || got != op {
+			t.Errorf("ParseOperation(%q) = %q, %v, want %q, true", op, got, ok, op)
+		}
+	}
+
+	for _, name := range []string{"", "PA", " pa", "rrrr", "s"} {
+		if got, ok := ParseOperation(name); ok || got != Invalid {
+			t.Errorf("ParseOperation(%q) = %q, %v, want Invalid, false", name, got, ok)
+		}
+	}
+}
+
+// TestInverse verifies that applying an operation and then its inverse restores both stacks.
+func TestInverse(t *testing.T) {
+	for _, op := range allOperations {
+		t.Run(string(op), func(t *testing.T) {
+			ds := NewDoubleStack[float64](1, 2, 3)
+			ds.B.PushBottom(4)
+			ds.B.PushBottom(5)
+			ds.B.PushBottom(6)
+
+			ds.ExecuteInstruction(op)
+			ds.ExecuteInstruction(op.Inverse())
+
+			if gotA := stackContents(ds, "A"); !slicesEqual(gotA, []float64{1, 2, 3}) {
+				t.Errorf("A after %s and %s = %v, want [1 2 3]", op, op.Inverse(), gotA)
+			}
+			if gotB := stackContents(ds, "B"); !slicesEqual(gotB, []float64{4, 5, 6}) {
+				t.Errorf("B after %s and %s = %v, want [4 5 6]", op, op.Inverse(), gotB)
+			}
+		})
+	}
+
+	if got := Invalid.Inverse(); got != Invalid {
+		t.Errorf("Invalid.Inverse() = %q, want Invalid", got)
+	}
+}
@@ -0,0 +1,52 @@
+package render
+
+import (
+	"cmp"
+	"iter"
+
+	"push-swap-go/internal/pushswap"
+)
+
+// Frame is the state of both stacks after a number of instructions, with
+// every value replaced by its rank.
+type Frame struct {
+	Step    int
+	Op      pushswap.Operation // The instruction executed last, Invalid for the initial state.
+	Applied bool               // Whether Op could be applied.
+	A       []int              // Ranks in stack A from top to bottom.
+	B       []int              // Ranks in stack B from top to bottom.
+}
+
+// stackRanks copies the ranks held by a stack from top to bottom.
+func stackRanks(s interface {
+	Len() int
+	All() iter.Seq2[int, int]
+}) []int {
+	ranks := make([]int, s.Len())
+	for i, rank := range s.All() {
+		ranks[i] = rank
+	}
+
+	return ranks
+}
+
+// Replay yields the initial state of the numbers and the state after every
+// instruction. Every yielded frame holds its own copy of the stacks.
+func Replay[T cmp.Ordered](nums []T, instructions []pushswap.Operation) iter.Seq[Frame] {
+	return func(yield func(Frame) bool) {
+		ds := pushswap.NewDoubleStack(pushswap.Ranks(nums)...)
+
+		if !yield(Frame{Applied: true, A: stackRanks(&ds.A), B: stackRanks(&ds.B)}) {
+			return
+		}
+
+		for i, op := range instructions {
+			applied := ds.ExecuteInstruction(op) != pushswap.Invalid
+			frame := Frame{Step: i + 1, Op: op, Applied: applied, A: stackRanks(&ds.A), B: stackRanks(&ds.B)}
+
+			if !yield(frame) {
+				return
+			}
+		}
+	}
+}
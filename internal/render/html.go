@@ -0,0 +1,164 @@
+package render
+
+import (
+	"cmp"
+	"fmt"
+	"html/template"
+	"io"
+
+	"push-swap-go/internal/pushswap"
+)
+
+// replayData is embedded into the HTML page, which replays the instructions
+// on the ranks itself instead of storing every frame.
+type replayData struct {
+	Ranks        []int                `json:"ranks"`
+	Instructions []pushswap.Operation `json:"instructions"`
+	Width        int                  `json:"width"`
+	Height       int                  `json:"height"`
+}
+
+var pageTemplate = template.Must(template.New("replay").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>push-swap replay</title>
+<style>
+body { background: #1e1e1e; color: #ddd; font-family: monospace; }
+#controls { margin: 8px 0; }
+#controls input[type=range] { vertical-align: middle; }
+</style>
+</head>
+<body>
+<div id="controls">
+<button id="play">play</button>
+<button id="back">&lt;</button>
+<button id="forward">&gt;</button>
+<input id="step" type="range" min="0" value="0">
+<label>delay <select id="delay">
+<option value="500">500ms</option>
+<option value="100">100ms</option>
+<option value="50" selected>50ms</option>
+<option value="10">10ms</option>
+<option value="1">1ms</option>
+</select></label>
+</div>
+<svg id="stacks"></svg>
+<script>
+const data = {{.}};
+const header = 40, gap = 20;
+const panel = (data.width - gap) / 2;
+const size = Math.max(1, data.ranks.length);
+const svg = document.getElementById("stacks");
+const slider = document.getElementById("step");
+const playButton = document.getElementById("play");
+svg.setAttribute("width", data.width);
+svg.setAttribute("height", data.height);
+svg.setAttribute("viewBox", "0 0 " + data.width + " " + data.height);
+slider.max = data.instructions.length;
+
+// Stacks hold ranks from top to bottom, states[i] after i instructions.
+function apply(a, b, op) {
+	const swap = s => { if (s.length > 1) [s[0], s[1]] = [s[1], s[0]]; };
+	const rotate = s => { if (s.length > 1) s.push(s.shift()); };
+	const reverse = s => { if (s.length > 1) s.unshift(s.pop()); };
+	switch (op) {
+	case "pa": if (b.length == 0) return false; a.unshift(b.shift()); break;
+	case "pb": if (a.length == 0) return false; b.unshift(a.shift()); break;
+	case "sa": swap(a); break;
+	case "sb": swap(b); break;
+	case "ss": swap(a); swap(b); break;
+	case "ra": rotate(a); break;
+	case "rb": rotate(b); break;
+	case "rr": rotate(a); rotate(b); break;
+	case "rra": reverse(a); break;
+	case "rrb": reverse(b); break;
+	case "rrr": reverse(a); reverse(b); break;
+	default: return false;
+	}
+	return true;
+}
+
+const states = [{a: data.ranks.slice(), b: [], applied: true}];
+for (const op of data.instructions) {
+	const last = states[states.length - 1];
+	const a = last.a.slice(), b = last.b.slice();
+	const applied = apply(a, b, op);
+	states.push({a: a, b: b, applied: applied});
+}
+
+function draw(step) {
+	const state = states[step];
+	let caption = "start";
+	if (step > 0) {
+		caption = "step " + step + ": " + data.instructions[step - 1] + (state.applied ? "" : " (not applied)");
+	}
+
+	const thickness = (data.height - header) / size;
+	let body = '<rect width="' + data.width + '" height="' + data.height + '" fill="#1e1e1e"/>';
+	body += '<text x="8" y="24" fill="#ddd" font-family="monospace" font-size="16">' + caption + ' / ' + data.instructions.length + '</text>';
+	[state.a, state.b].forEach((ranks, stack) => {
+		const x = stack * (panel + gap);
+		ranks.forEach((rank, i) => {
+			const length = (rank + 1) * panel / size;
+			const hue = Math.floor(rank * 300 / size);
+			body += '<rect x="' + x + '" y="' + (header + i * thickness) + '" width="' + length +
+				'" height="' + thickness + '" fill="hsl(' + hue + ',70%,55%)"/>';
+		});
+	});
+	svg.innerHTML = body;
+	slider.value = step;
+}
+
+let step = 0, timer = null;
+function pause() {
+	clearInterval(timer);
+	timer = null;
+	playButton.textContent = "play";
+}
+function play() {
+	if (step >= data.instructions.length) step = 0;
+	timer = setInterval(() => {
+		if (step >= data.instructions.length) { pause(); return; }
+		draw(++step);
+	}, Number(document.getElementById("delay").value));
+	playButton.textContent = "pause";
+}
+
+playButton.onclick = () => timer ? pause() : play();
+document.getElementById("delay").onchange = () => { if (timer) { pause(); play(); } };
+document.getElementById("forward").onclick = () => { pause(); draw(step = Math.min(step + 1, data.instructions.length)); };
+document.getElementById("back").onclick = () => { pause(); draw(step = Math.max(step - 1, 0)); };
+slider.oninput = () => { pause(); draw(step = Number(slider.value)); };
+draw(0);
+</script>
+</body>
+</html>
+`))
+
+// WriteHTML writes a self-contained page replaying the instructions on the
+// numbers, with controls to play, pause and seek. It loads nothing from the
+// network.
+func WriteHTML[T cmp.Ordered](w io.Writer, nums []T, instructions []pushswap.Operation) error {
+	data := replayData{
+		Ranks:        pushswap.Ranks(nums),
+		Instructions: instructions,
+		Width:        Width,
+		Height:       Height,
+	}
+
+	if data.Ranks == nil {
+		data.Ranks = []int{}
+	}
+
+	if data.Instructions == nil {
+		data.Instructions = []pushswap.Operation{}
+	}
+
+	err := pageTemplate.Execute(w, data)
+	if err != nil {
+		return fmt.Errorf("writing page: %v", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,187 @@
+package render
+
+import (
+	"cmp"
+	"fmt"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/png"
+	"io"
+	"math"
+	"time"
+
+	"push-swap-go/internal/pushswap"
+)
+
+// Palette indices of the colours that are not bars.
+const (
+	background uint8 = iota
+	progress
+	notApplied
+	firstBar
+)
+
+// Height of the progress bar shown above the stacks.
+const progressHeight = 8
+
+// palette holds the fixed colours followed by the bar colours, ordered by hue.
+var palette = func() color.Palette {
+	p := color.Palette{
+		color.RGBA{0x1e, 0x1e, 0x1e, 0xff},
+		color.RGBA{0xdd, 0xdd, 0xdd, 0xff},
+		color.RGBA{0xe0, 0x40, 0x40, 0xff},
+	}
+
+	bars := 256 - len(p)
+	for i := range bars {
+		p = append(p, hslColor(float64(i*300)/float64(bars), 0.7, 0.55))
+	}
+
+	return p
+}()
+
+// hslColor converts a hue in degrees, a saturation and a lightness to RGB.
+func hslColor(h, s, l float64) color.RGBA {
+	c := (1 - math.Abs(2*l-1)) * s
+	x := c * (1 - math.Abs(math.Mod(h/60, 2)-1))
+	m := l - c/2
+
+	var r, g, b float64
+	switch {
+	case h < 60:
+		r, g, b = c, x, 0
+	case h < 120:
+		r, g, b = x, c, 0
+	case h < 180:
+		r, g, b = 0, c, x
+	case h < 240:
+		r, g, b = 0, x, c
+	case h < 300:
+		r, g, b = x, 0, c
+	default:
+		r, g, b = c, 0, x
+	}
+
+	return color.RGBA{uint8((r + m) * 255), uint8((g + m) * 255), uint8((b + m) * 255), 0xff}
+}
+
+// fill paints a rectangle of the image with a palette colour.
+func fill(img *image.Paletted, r image.Rectangle, index uint8) {
+	r = r.Intersect(img.Rect)
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			img.SetColorIndex(x, y, index)
+		}
+	}
+}
+
+// DrawFrame draws a frame with stack A on the left and stack B on the right.
+// Images have no text, so the position of the frame among `total` steps is
+// shown as a progress bar, drawn in red if the last instruction could not be
+// applied.
+func DrawFrame(f Frame, total int) *image.Paletted {
+	img := image.NewPaletted(image.Rect(0, 0, Width, Height), palette)
+	size := max(1, len(f.A)+len(f.B))
+
+	barColour := progress
+	if !f.Applied {
+		barColour = notApplied
+	}
+
+	done := Width
+	if total > 0 {
+		done = f.Step * Width / total
+	}
+
+	fill(img, image.Rect(0, 0, done, progressHeight), barColour)
+
+	bars := len(palette) - int(firstBar)
+	for stackIdx, ranks := range [][]int{f.A, f.B} {
+		x := stackIdx * (panelWidth + panelGap)
+
+		for i, rank := range ranks {
+			top := headerHeight + i*(Height-headerHeight)/size
+			bottom := max(top+1, headerHeight+(i+1)*(Height-headerHeight)/size)
+			length := max(1, (rank+1)*panelWidth/size)
+
+			fill(img, image.Rect(x, top, x+length, bottom), firstBar+uint8(rank*bars/size))
+		}
+	}
+
+	return img
+}
+
+// WritePNG draws a frame as a PNG image.
+func WritePNG(w io.Writer, f Frame, total int) error {
+	return png.Encode(w, DrawFrame(f, total))
+}
+
+// GIFOptions control which frames of a replay are drawn and for how long.
+type GIFOptions struct {
+	Every     int           // Draw every Every-th step, 1 if not positive.
+	MaxFrames int           // Skip more steps if needed to stay below this many frames, unlimited if not positive.
+	Delay     time.Duration // Time every frame is shown.
+	LastDelay time.Duration // Time the final state is shown before looping.
+}
+
+// frameCount is the number of frames drawn for `steps` instructions when
+// drawing every `every`-th step, including the initial and final states.
+func frameCount(steps, every int) int {
+	count := steps/every + 1
+	if steps%every != 0 {
+		count++
+	}
+
+	return count
+}
+
+// stride returns the number of steps between drawn frames.
+func (opts GIFOptions) stride(steps int) int {
+	every := max(1, opts.Every)
+
+	if opts.MaxFrames > 0 {
+		// The initial and final states are always drawn.
+		limit := max(2, opts.MaxFrames)
+		for frameCount(steps, every) > limit {
+			every++
+		}
+	}
+
+	return every
+}
+
+// hundredths converts a duration to the unit of GIF frame delays.
+func hundredths(d time.Duration) int {
+	return max(1, int(d/(10*time.Millisecond)))
+}
+
+// WriteGIF writes an animated GIF replaying the instructions on the numbers.
+// Long replays are shortened by skipping steps according to the options;
+// the initial and final states are always drawn.
+func WriteGIF[T cmp.Ordered](w io.Writer, nums []T, instructions []pushswap.Operation, opts GIFOptions) error {
+	every := opts.stride(len(instructions))
+	anim := &gif.GIF{}
+
+	for f := range Replay(nums, instructions) {
+		last := f.Step == len(instructions)
+		if f.Step%every != 0 && !last {
+			continue
+		}
+
+		delay := opts.Delay
+		if last {
+			delay = max(opts.Delay, opts.LastDelay)
+		}
+
+		anim.Image = append(anim.Image, DrawFrame(f, len(instructions)))
+		anim.Delay = append(anim.Delay, hundredths(delay))
+	}
+
+	err := gif.EncodeAll(w, anim)
+	if err != nil {
+		return fmt.Errorf("encoding GIF: %v", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,261 @@
+package render
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"image/gif"
+	"image/png"
+	"io"
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"push-swap-go/internal/pushswap"
+)
+
+func TestReplay(t *testing.T) {
+	nums := []float64{2.5, -1, 10}
+	instructions := []pushswap.Operation{pushswap.PB, pushswap.PA, pushswap.PA, pushswap.RA}
+
+	want := []Frame{
+		{Step: 0, Op: pushswap.Invalid, Applied: true, A: []int{1, 0, 2}, B: []int{}},
+		{Step: 1, Op: pushswap.PB, Applied: true, A: []int{0, 2}, B: []int{1}},
+		{Step: 2, Op: pushswap.PA, Applied: true, A: []int{1, 0, 2}, B: []int{}},
+		{Step: 3, Op: pushswap.PA, Applied: false, A: []int{1, 0, 2}, B: []int{}},
+		{Step: 4, Op: pushswap.RA, Applied: true, A: []int{0, 2, 1}, B: []int{}},
+	}
+
+	var got []Frame
+	for f := range Replay(nums, instructions) {
+		got = append(got, f)
+	}
+
+	if len(got) != len(want) {
+		t.Fatalf("Replay yielded %d frames, want %d", len(got), len(want))
+	}
+
+	for i := range want {
+		g, w := got[i], want[i]
+		if g.Step != w.Step || g.Op != w.Op || g.Applied != w.Applied || !slices.Equal(g.A, w.A) || !slices.Equal(g.B, w.B) {
+			t.Errorf("frame %d = %+v, want %+v", i, g, w)
+		}
+	}
+}
+
+func TestReplayStopsEarly(t *testing.T) {
+	instructions := []pushswap.Operation{pushswap.PB, pushswap.PB, pushswap.PB}
+
+	frames := 0
+	for range Replay([]int{3, 2, 1}, instructions) {
+		frames++
+		if frames == 2 {
+			break
+		}
+	}
+
+	if frames != 2 {
+		t.Errorf("got %d frames, want 2", frames)
+	}
+}
+
+func TestWriteSVG(t *testing.T) {
+	tests := []struct {
+		name    string
+		frame   Frame
+		caption string
+	}{
+		{name: "start", frame: Frame{Applied: true, A: []int{2, 0, 1}}, caption: "start"},
+		{name: "both stacks", frame: Frame{Step: 3, Op: pushswap.PB, Applied: true, A: []int{2}, B: []int{0, 1}}, caption: "step 3: pb"},
+		{name: "not applied", frame: Frame{Step: 1, Op: pushswap.PA, A: []int{0}}, caption: "step 1: pa (not applied)"},
+		{name: "empty", frame: Frame{Applied: true}, caption: "start"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := WriteSVG(&buf, tt.frame)
+			if err != nil {
+				t.Fatalf("WriteSVG returned error: %v", err)
+			}
+
+			rects, texts := 0, []string{}
+			decoder := xml.NewDecoder(&buf)
+			for {
+				token, err := decoder.Token()
+				if err == io.EOF {
+					break
+				}
+				if err != nil {
+					t.Fatalf("invalid SVG: %v", err)
+				}
+
+				if start, ok := token.(xml.StartElement); ok {
+					switch start.Name.Local {
+					case "rect":
+						rects++
+					case "text":
+						var text string
+						decoder.DecodeElement(&text, &start)
+						texts = append(texts, text)
+					}
+				}
+			}
+
+			// One background rectangle plus one bar per value.
+			if want := 1 + len(tt.frame.A) + len(tt.frame.B); rects != want {
+				t.Errorf("got %d rects, want %d", rects, want)
+			}
+
+			if !slices.Contains(texts, tt.caption) {
+				t.Errorf("captions %q do not contain %q", texts, tt.caption)
+			}
+		})
+	}
+}
+
+func TestWriteHTML(t *testing.T) {
+	instructions := []pushswap.Operation{pushswap.PB, pushswap.SA, pushswap.PA}
+
+	var buf bytes.Buffer
+	err := WriteHTML(&buf, []int{5, -3, 8}, instructions)
+	if err != nil {
+		t.Fatalf("WriteHTML returned error: %v", err)
+	}
+
+	page := buf.String()
+	for _, external := range []string{"src=", "href=", "http:", "https:"} {
+		if strings.Contains(page, external) {
+			t.Errorf("page references external resources (%q)", external)
+		}
+	}
+
+	match := regexp.MustCompile(`const data = (.*);`).FindStringSubmatch(page)
+	if match == nil {
+		t.Fatalf("page does not embed the replay data")
+	}
+
+	var data replayData
+	err = json.Unmarshal([]byte(match[1]), &data)
+	if err != nil {
+		t.Fatalf("embedded data is not valid JSON: %v", err)
+	}
+
+	if !slices.Equal(data.Ranks, []int{1, 0, 2}) {
+		t.Errorf("embedded ranks = %v, want [1 0 2]", data.Ranks)
+	}
+
+	if !slices.Equal(data.Instructions, instructions) {
+		t.Errorf("embedded instructions = %v, want %v", data.Instructions, instructions)
+	}
+}
+
+func TestDrawFrame(t *testing.T) {
+	f := Frame{Step: 1, Op: pushswap.PB, Applied: true, A: []int{1, 0}, B: []int{2}}
+	img := DrawFrame(f, 2)
+
+	// The progress bar covers half of the width after one of two steps.
+	if got := img.ColorIndexAt(Width/2-1, 0); got != progress {
+		t.Errorf("progress bar colour = %d, want %d", got, progress)
+	}
+	if got := img.ColorIndexAt(Width/2, 0); got != background {
+		t.Errorf("colour after the progress bar = %d, want %d", got, background)
+	}
+
+	// Every bar is as long as its rank plus one, out of the panel width.
+	barHeight := (Height - headerHeight) / 3
+	tests := []struct {
+		x, row, length int
+	}{
+		{x: 0, row: 0, length: 2 * panelWidth / 3},
+		{x: 0, row: 1, length: panelWidth / 3},
+		{x: panelWidth + panelGap, row: 0, length: panelWidth},
+	}
+
+	for _, tt := range tests {
+		y := headerHeight + tt.row*barHeight
+		if got := img.ColorIndexAt(tt.x+tt.length-1, y); got < firstBar {
+			t.Errorf("bar at x=%d row %d ends before %d", tt.x, tt.row, tt.length)
+		}
+		if tt.length < panelWidth {
+			if got := img.ColorIndexAt(tt.x+tt.length, y); got != background {
+				t.Errorf("bar at x=%d row %d is longer than %d", tt.x, tt.row, tt.length)
+			}
+		}
+	}
+
+	f.Applied = false
+	if got := DrawFrame(f, 2).ColorIndexAt(0, 0); got != notApplied {
+		t.Errorf("progress bar colour of a step not applied = %d, want %d", got, notApplied)
+	}
+}
+
+func TestWritePNG(t *testing.T) {
+	var buf bytes.Buffer
+	err := WritePNG(&buf, Frame{Applied: true, A: []int{0, 1}}, 0)
+	if err != nil {
+		t.Fatalf("WritePNG returned error: %v", err)
+	}
+
+	img, err := png.Decode(&buf)
+	if err != nil {
+		t.Fatalf("invalid PNG: %v", err)
+	}
+
+	if size := img.Bounds().Size(); size.X != Width || size.Y != Height {
+		t.Errorf("image size = %v, want %dx%d", size, Width, Height)
+	}
+}
+
+func TestGIFOptionsStride(t *testing.T) {
+	tests := []struct {
+		name  string
+		opts  GIFOptions
+		steps int
+		want  int
+	}{
+		{name: "defaults", opts: GIFOptions{}, steps: 100, want: 1},
+		{name: "every", opts: GIFOptions{Every: 5}, steps: 100, want: 5},
+		{name: "below max frames", opts: GIFOptions{MaxFrames: 200}, steps: 100, want: 1},
+		{name: "max frames", opts: GIFOptions{MaxFrames: 11}, steps: 100, want: 10},
+		{name: "max frames with final state", opts: GIFOptions{MaxFrames: 10}, steps: 100, want: 12},
+		{name: "max frames overrides every", opts: GIFOptions{Every: 2, MaxFrames: 6}, steps: 100, want: 20},
+		{name: "tiny max frames", opts: GIFOptions{MaxFrames: 1}, steps: 100, want: 100},
+		{name: "no steps", opts: GIFOptions{MaxFrames: 1}, steps: 0, want: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.opts.stride(tt.steps); got != tt.want {
+				t.Errorf("stride(%d) = %d, want %d", tt.steps, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriteGIF(t *testing.T) {
+	instructions := []pushswap.Operation{pushswap.PB, pushswap.PB, pushswap.SA, pushswap.PA, pushswap.PA}
+	opts := GIFOptions{Every: 2, Delay: 50 * time.Millisecond, LastDelay: time.Second}
+
+	var buf bytes.Buffer
+	err := WriteGIF(&buf, []int{4, 8, 1}, instructions, opts)
+	if err != nil {
+		t.Fatalf("WriteGIF returned error: %v", err)
+	}
+
+	anim, err := gif.DecodeAll(&buf)
+	if err != nil {
+		t.Fatalf("invalid GIF: %v", err)
+	}
+
+	// Steps 0, 2, 4 and the final step 5.
+	if len(anim.Image) != 4 {
+		t.Fatalf("got %d frames, want 4", len(anim.Image))
+	}
+
+	if want := []int{5, 5, 5, 100}; !slices.Equal(anim.Delay, want) {
+		t.Errorf("delays = %v, want %v", anim.Delay, want)
+	}
+}
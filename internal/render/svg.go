@@ -0,0 +1,72 @@
+package render
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+)
+
+// Dimensions of the rendered images in pixels.
+const (
+	Width        = 800
+	Height       = 440
+	headerHeight = 40
+	panelGap     = 20
+)
+
+// panelWidth is the width available to the bars of one stack.
+const panelWidth = (Width - panelGap) / 2
+
+// hue spreads the ranks over the colour wheel, stopping short of red wrapping
+// back around so the smallest and largest ranks look different.
+func hue(rank, size int) int {
+	return rank * 300 / max(1, size)
+}
+
+// barGeometry returns the length and thickness of the bar for a rank in a
+// frame holding `size` values.
+func barGeometry(rank, size int) (length, thickness float64) {
+	size = max(1, size)
+	length = float64((rank+1)*panelWidth) / float64(size)
+	thickness = float64(Height-headerHeight) / float64(size)
+	return length, thickness
+}
+
+// caption describes the instruction that produced the frame.
+func caption(f Frame) string {
+	if f.Step == 0 {
+		return "start"
+	}
+
+	if !f.Applied {
+		return fmt.Sprintf("step %d: %s (not applied)", f.Step, f.Op)
+	}
+
+	return fmt.Sprintf("step %d: %s", f.Step, f.Op)
+}
+
+// WriteSVG draws a frame as a standalone SVG image with stack A on the left
+// and stack B on the right.
+func WriteSVG(w io.Writer, f Frame) error {
+	output := bufio.NewWriter(w)
+	size := len(f.A) + len(f.B)
+
+	fmt.Fprintf(output, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d">`+"\n",
+		Width, Height, Width, Height)
+	fmt.Fprintf(output, `<rect width="%d" height="%d" fill="#1e1e1e"/>`+"\n", Width, Height)
+	fmt.Fprintf(output, `<text x="8" y="24" fill="#ddd" font-family="monospace" font-size="16">%s</text>`+"\n", caption(f))
+	fmt.Fprintf(output, `<text x="%d" y="24" fill="#ddd" font-family="monospace" font-size="16" text-anchor="end">A | B</text>`+"\n", Width-8)
+
+	for stackIdx, ranks := range [][]int{f.A, f.B} {
+		x := stackIdx * (panelWidth + panelGap)
+
+		for i, rank := range ranks {
+			length, thickness := barGeometry(rank, size)
+			fmt.Fprintf(output, `<rect x="%d" y="%.2f" width="%.2f" height="%.2f" fill="hsl(%d,70%%,55%%)"/>`+"\n",
+				x, headerHeight+float64(i)*thickness, length, thickness, hue(rank, size))
+		}
+	}
+
+	fmt.Fprintln(output, "</svg>")
+	return output.Flush()
+}
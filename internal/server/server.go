@@ -0,0 +1,281 @@
+// Package server implements the JSON API solving and checking push-swap
+// instructions over HTTP.
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"time"
+
+	"push-swap-go/internal/pushswap"
+)
+
+// Config limits the work a single request can cause.
+type Config struct {
+	MaxBodyBytes int64         // Largest accepted request body, DefaultConfig's if not positive.
+	MaxNumbers   int           // Largest accepted number list, unlimited if not positive.
+	Timeout      time.Duration // Time after which a request is abandoned, unlimited if not positive.
+}
+
+// DefaultConfig holds the limits used unless configured otherwise.
+var DefaultConfig = Config{
+	MaxBodyBytes: 1 << 20,
+	MaxNumbers:   1000, // Takes the Turk algorithm about 0.8s, well under Timeout.
+	Timeout:      10 * time.Second,
+}
+
+// options are shared by all requests taking numbers.
+type options struct {
+	AllowDuplicates bool `json:"allow_duplicates"`
+	// NumberMode defaults to accepting finite numbers only.
+	NumberMode pushswap.NumberMode `json:"number_mode"`
+	// Equality defaults to numeric, where -0 and 0 are duplicates.
+	Equality pushswap.Equality `json:"equality"`
+}
+
+type solveRequest struct {
+	Numbers   []json.Number `json:"numbers"`
+	Algorithm string        `json:"algorithm"`
+	Options   options       `json:"options"`
+}
+
+type checkRequest struct {
+	Numbers      []json.Number `json:"numbers"`
+	Instructions []string      `json:"instructions"`
+	Options      options       `json:"options"`
+}
+
+type checkResponse struct {
+	Verdict      string `json:"verdict"`
+	Count        int    `json:"count"`
+	FirstInvalid int    `json:"first_invalid"` // Index of the first instruction that could not be applied, or -1.
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+	// Index and Token locate a number that was not accepted, and
+	// DuplicateOf the index of the number it equals if it is a duplicate.
+	Index       *int   `json:"index,omitempty"`
+	Token       string `json:"token,omitempty"`
+	DuplicateOf *int   `json:"duplicate_of,omitempty"`
+}
+
+// algorithms maps the names accepted by /solve to the solvers. They give up
+// once the request's context is done.
+var algorithms = map[string]func(context.Context, []float64) ([]pushswap.Operation, error){
+	"turk": pushswap.TurkAlgorithmContext[float64],
+}
+
+// requestError is reported to the client with its status code.
+type requestError struct {
+	status int
+	err    error
+}
+
+func (e *requestError) Error() string {
+	return e.err.Error()
+}
+
+func (e *requestError) Unwrap() error {
+	return e.err
+}
+
+func badRequest(format string, a ...any) error {
+	return &requestError{status: http.StatusBadRequest, err: fmt.Errorf(format, a...)}
+}
+
+type server struct {
+	config Config
+}
+
+// New returns a handler serving `POST /solve` and `POST /check`.
+func New(config Config) http.Handler {
+	if config.MaxBodyBytes <= 0 {
+		config.MaxBodyBytes = DefaultConfig.MaxBodyBytes
+	}
+
+	s := &server{config: config}
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("POST /solve", handle(s, s.solve))
+	mux.HandleFunc("POST /check", handle(s, s.check))
+	return mux
+}
+
+// handle decodes the request body, runs fn with the request's context
+// bounded by the configured timeout and encodes its result.
+func handle[Req, Resp any](s *server, fn func(context.Context, Req) (Resp, error)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req Req
+
+		decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, s.config.MaxBodyBytes))
+		decoder.DisallowUnknownFields()
+
+		err := decoder.Decode(&req)
+		if err != nil {
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				writeError(w, &requestError{status: http.StatusRequestEntityTooLarge, err: fmt.Errorf("request body larger than %d bytes", tooLarge.Limit)})
+			} else {
+				writeError(w, badRequest("decoding request: %v", err))
+			}
+
+			return
+		}
+
+		ctx := r.Context()
+		if s.config.Timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, s.config.Timeout)
+			defer cancel()
+		}
+
+		resp, err := fn(ctx, req)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		writeJSON(w, http.StatusOK, resp)
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	status := http.StatusInternalServerError
+
+	var reqErr *requestError
+	switch {
+	case errors.As(err, &reqErr):
+		status = reqErr.status
+	case errors.Is(err, context.DeadlineExceeded):
+		status = http.StatusServiceUnavailable
+		err = errors.New("request timed out")
+	case errors.Is(err, context.Canceled):
+		status = http.StatusServiceUnavailable
+		err = errors.New("request cancelled")
+	}
+
+	resp := errorResponse{Error: err.Error()}
+
+	var parseErr *pushswap.ParseError
+	var dupErr *pushswap.DuplicateError
+	switch {
+	case errors.As(err, &parseErr):
+		resp.Index, resp.Token = &parseErr.Index, parseErr.Token
+	case errors.As(err, &dupErr):
+		resp.Index, resp.Token, resp.DuplicateOf = &dupErr.SecondIndex, dupErr.Token, &dupErr.FirstIndex
+	}
+
+	writeJSON(w, status, resp)
+}
+
+// parseNumbers validates the numbers of a request like the command line
+// tools do.
+func (s *server) parseNumbers(numbers []json.Number, opts options) ([]float64, error) {
+	if s.config.MaxNumbers > 0 && len(numbers) > s.config.MaxNumbers {
+		return nil, badRequest("too many numbers: %d, the limit is %d", len(numbers), s.config.MaxNumbers)
+	}
+
+	numStrings := make([]string, len(numbers))
+	for i, n := range numbers {
+		numStrings[i] = n.String()
+	}
+
+	nums, err := pushswap.ParseNumberSliceWithOptions(numStrings, pushswap.ParseOptions{
+		Mode:            opts.NumberMode,
+		AllowDuplicates: opts.AllowDuplicates,
+		Equality:        opts.Equality,
+	})
+	if err != nil {
+		return nil, &requestError{status: http.StatusBadRequest, err: err}
+	}
+
+	return nums, nil
+}
+
+// run calls fn in its own goroutine and returns its result, or the context's
+// error if it is done first. fn keeps running in the background after a
+// timeout, so it must take time linear in the request size, which
+// MaxBodyBytes bounds.
+func run[T any](ctx context.Context, fn func() T) (T, error) {
+	result := make(chan T, 1)
+	go func() {
+		result <- fn()
+	}()
+
+	select {
+	case r := <-result:
+		return r, nil
+	case <-ctx.Done():
+		var zero T
+		return zero, ctx.Err()
+	}
+}
+
+func (s *server) solve(ctx context.Context, req solveRequest) (pushswap.Result[json.Number], error) {
+	if req.Algorithm == "" {
+		req.Algorithm = "turk"
+	}
+
+	algorithm, ok := algorithms[req.Algorithm]
+	if !ok {
+		return pushswap.Result[json.Number]{}, badRequest("unknown algorithm %q", req.Algorithm)
+	}
+
+	nums, err := s.parseNumbers(req.Numbers, req.Options)
+	if err != nil {
+		return pushswap.Result[json.Number]{}, err
+	}
+
+	start := time.Now()
+	instructions, err := algorithm(ctx, nums)
+	if err != nil {
+		return pushswap.Result[json.Number]{}, err
+	}
+
+	return pushswap.NewResult(req.Numbers, req.Algorithm, instructions, time.Since(start)), nil
+}
+
+func (s *server) check(ctx context.Context, req checkRequest) (checkResponse, error) {
+	nums, err := s.parseNumbers(req.Numbers, req.Options)
+	if err != nil {
+		return checkResponse{}, err
+	}
+
+	instructions := make([]pushswap.Operation, len(req.Instructions))
+	for i, name := range req.Instructions {
+		op, ok := pushswap.ParseOperation(name)
+		if !ok {
+			return checkResponse{}, badRequest("instruction %d: unrecognised command %q", i, name)
+		}
+
+		instructions[i] = op
+	}
+
+	return run(ctx, func() checkResponse {
+		resp := checkResponse{Verdict: "KO", Count: len(instructions), FirstInvalid: -1}
+		ds := pushswap.NewDoubleStack(nums...)
+
+		for i, op := range instructions {
+			if ds.ExecuteInstruction(op) == pushswap.Invalid && resp.FirstInvalid < 0 {
+				resp.FirstInvalid = i
+			}
+		}
+
+		if ds.Holds(slices.Sorted(slices.Values(nums))) {
+			resp.Verdict = "OK"
+		}
+
+		return resp
+	})
+}
@@ -0,0 +1,216 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"push-swap-go/internal/pushswap"
+)
+
+// post sends a request body to the handler and decodes the JSON response.
+func post(t *testing.T, handler http.Handler, path, body string, resp any) int {
+	t.Helper()
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, path, strings.NewReader(body)))
+
+	if ct := rec.Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("Content-Type = %q, want application/json", ct)
+	}
+
+	err := json.Unmarshal(rec.Body.Bytes(), resp)
+	if err != nil {
+		t.Fatalf("decoding response %q: %v", rec.Body.String(), err)
+	}
+
+	return rec.Code
+}
+
+func TestSolve(t *testing.T) {
+	handler := New(DefaultConfig)
+
+	tests := []struct {
+		name string
+		body string
+		nums []float64
+	}{
+		{name: "default algorithm", body: `{"numbers": [3, 1, 2, -5, 10]}`, nums: []float64{3, 1, 2, -5, 10}},
+		{name: "named algorithm", body: `{"numbers": [2, 1], "algorithm": "turk"}`, nums: []float64{2, 1}},
+		{name: "numbers as strings", body: `{"numbers": ["2.5", "-1"]}`, nums: []float64{2.5, -1}},
+		{name: "sorted", body: `{"numbers": [1, 2, 3]}`, nums: []float64{1, 2, 3}},
+		{name: "bitwise equality", body: `{"numbers": [0, "-0", 1], "options": {"equality": "bitwise"}}`, nums: []float64{0, 0, 1}},
+		{name: "duplicates allowed", body: `{"numbers": [2, 1, 2], "options": {"allow_duplicates": true}}`, nums: []float64{2, 1, 2}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var resp pushswap.Result[json.Number]
+			if code := post(t, handler, "/solve", tt.body, &resp); code != http.StatusOK {
+				t.Fatalf("status = %d, want %d", code, http.StatusOK)
+			}
+
+			if !pushswap.Verify(tt.nums, resp.Instructions) {
+				t.Errorf("instructions %v do not sort %v", resp.Instructions, tt.nums)
+			}
+
+			if resp.Algorithm != "turk" || len(resp.Input) != len(tt.nums) || resp.Count != len(resp.Instructions) {
+				t.Errorf("unexpected response %+v", resp)
+			}
+
+			total := 0
+			for _, n := range resp.OpCounts {
+				total += n
+			}
+
+			if total != resp.Count {
+				t.Errorf("op counts %v do not add up to %d", resp.OpCounts, resp.Count)
+			}
+		})
+	}
+}
+
+func TestSolveFields(t *testing.T) {
+	handler := New(DefaultConfig)
+
+	// The fields are those written by push-swap -output-format json.
+	var resp map[string]json.RawMessage
+	if code := post(t, handler, "/solve", `{"numbers": [3, 1, 2]}`, &resp); code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", code, http.StatusOK)
+	}
+
+	for _, field := range []string{"input", "algorithm", "instructions", "count", "op_counts", "duration_ns"} {
+		if _, ok := resp[field]; !ok {
+			t.Errorf("response %v has no %q field", resp, field)
+		}
+	}
+
+	if string(resp["input"]) != "[3,1,2]" || string(resp["count"]) != "1" {
+		t.Errorf("input = %s, count = %s, want [3,1,2] and 1", resp["input"], resp["count"])
+	}
+}
+
+func TestCheck(t *testing.T) {
+	handler := New(DefaultConfig)
+
+	tests := []struct {
+		name string
+		body string
+		want checkResponse
+	}{
+		{name: "OK", body: `{"numbers": [2, 1, 3], "instructions": ["sa"]}`, want: checkResponse{Verdict: "OK", Count: 1, FirstInvalid: -1}},
+		{name: "already sorted", body: `{"numbers": [1, 2], "instructions": []}`, want: checkResponse{Verdict: "OK", Count: 0, FirstInvalid: -1}},
+		{name: "KO", body: `{"numbers": [2, 1, 3], "instructions": ["ra"]}`, want: checkResponse{Verdict: "KO", Count: 1, FirstInvalid: -1}},
+		{name: "invalid step", body: `{"numbers": [2, 1], "instructions": ["pa", "sa"]}`, want: checkResponse{Verdict: "OK", Count: 2, FirstInvalid: 0}},
+		{name: "left in B", body: `{"numbers": [1, 2], "instructions": ["pb"]}`, want: checkResponse{Verdict: "KO", Count: 1, FirstInvalid: -1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var resp checkResponse
+			if code := post(t, handler, "/check", tt.body, &resp); code != http.StatusOK {
+				t.Fatalf("status = %d, want %d", code, http.StatusOK)
+			}
+
+			if resp != tt.want {
+				t.Errorf("response = %+v, want %+v", resp, tt.want)
+			}
+		})
+	}
+}
+
+func TestErrors(t *testing.T) {
+	handler := New(Config{MaxBodyBytes: 128, MaxNumbers: 5})
+
+	tests := []struct {
+		name   string
+		path   string
+		body   string
+		status int
+		error  string
+	}{
+		{name: "malformed", path: "/solve", body: `{"numbers": [1, 2`, status: http.StatusBadRequest, error: "decoding request"},
+		{name: "unknown field", path: "/solve", body: `{"nums": [1, 2]}`, status: http.StatusBadRequest, error: "unknown field"},
+		{name: "not a number", path: "/solve", body: `{"numbers": ["one"]}`, status: http.StatusBadRequest, error: "decoding request"},
+		{name: "duplicates", path: "/solve", body: `{"numbers": [1, 1]}`, status: http.StatusBadRequest, error: "duplicate"},
+		{name: "not finite", path: "/solve", body: `{"numbers": [1, 1e400]}`, status: http.StatusBadRequest, error: "too large to be finite"},
+		{name: "outside int32", path: "/check", body: `{"numbers": [1, 3000000000], "options": {"number_mode": "int32"}}`, status: http.StatusBadRequest, error: "outside the 32-bit integer range"},
+		{name: "negative zero", path: "/solve", body: `{"numbers": [0, -0]}`, status: http.StatusBadRequest, error: `duplicate number "-0" at index 1`},
+		{name: "unknown number mode", path: "/solve", body: `{"numbers": [1], "options": {"number_mode": "int8"}}`, status: http.StatusBadRequest, error: "unknown number mode"},
+		{name: "unknown algorithm", path: "/solve", body: `{"numbers": [1], "algorithm": "bogo"}`, status: http.StatusBadRequest, error: "unknown algorithm"},
+		{name: "too many numbers", path: "/check", body: `{"numbers": [1, 2, 3, 4, 5, 6]}`, status: http.StatusBadRequest, error: "too many numbers"},
+		{name: "unknown instruction", path: "/check", body: `{"numbers": [1], "instructions": ["sa", "xx"]}`, status: http.StatusBadRequest, error: `instruction 1: unrecognised command "xx"`},
+		{name: "body too large", path: "/check", body: `{"numbers": [1], "instructions": [` + strings.Repeat(`"sa",`, 40) + `"sa"]}`, status: http.StatusRequestEntityTooLarge, error: "larger than 128 bytes"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var resp errorResponse
+			if code := post(t, handler, tt.path, tt.body, &resp); code != tt.status {
+				t.Errorf("status = %d, want %d", code, tt.status)
+			}
+
+			if !strings.Contains(resp.Error, tt.error) {
+				t.Errorf("error = %q, want it to contain %q", resp.Error, tt.error)
+			}
+		})
+	}
+}
+
+func TestNumberErrorLocation(t *testing.T) {
+	handler := New(DefaultConfig)
+
+	var resp errorResponse
+	post(t, handler, "/solve", `{"numbers": [3, 1, 2, 1.0]}`, &resp)
+	if resp.Index == nil || *resp.Index != 3 || resp.Token != "1.0" || resp.DuplicateOf == nil || *resp.DuplicateOf != 1 {
+		t.Errorf("duplicate error = %+v, want index 3, token 1.0, duplicate of 1", resp)
+	}
+
+	resp = errorResponse{}
+	post(t, handler, "/check", `{"numbers": [3, 1.5], "options": {"number_mode": "int32"}}`, &resp)
+	if resp.Index == nil || *resp.Index != 1 || resp.Token != "1.5" || resp.DuplicateOf != nil {
+		t.Errorf("parse error = %+v, want index 1, token 1.5", resp)
+	}
+}
+
+func TestMethodNotAllowed(t *testing.T) {
+	rec := httptest.NewRecorder()
+	New(DefaultConfig).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/solve", nil))
+
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusMethodNotAllowed)
+	}
+}
+
+func TestTimeout(t *testing.T) {
+	stopped := make(chan struct{})
+
+	algorithms["slow"] = func(ctx context.Context, nums []float64) ([]pushswap.Operation, error) {
+		<-ctx.Done()
+		close(stopped)
+		return nil, ctx.Err()
+	}
+	defer delete(algorithms, "slow")
+
+	handler := New(Config{Timeout: 10 * time.Millisecond})
+
+	var resp errorResponse
+	if code := post(t, handler, "/solve", `{"numbers": [2, 1], "algorithm": "slow"}`, &resp); code != http.StatusServiceUnavailable {
+		t.Errorf("status = %d, want %d", code, http.StatusServiceUnavailable)
+	}
+
+	if resp.Error != "request timed out" {
+		t.Errorf("error = %q, want %q", resp.Error, "request timed out")
+	}
+
+	// The solver is told to stop rather than left running.
+	select {
+	case <-stopped:
+	case <-time.After(time.Second):
+		t.Errorf("the solver kept running after the timeout")
+	}
+}
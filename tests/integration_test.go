@@ -1,12 +1,20 @@
 package tests
 
 import (
+	"bufio"
 	"bytes"
+	"encoding/json"
 	"fmt"
+	"image/gif"
 	"io"
+	"math"
+	"net/http"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
 	"strings"
 	"testing"
 )
@@ -60,6 +68,20 @@ func buildBinaries(t *testing.T) (pushSwapPath, checkerPath string) {
 	return pushSwapPath, checkerPath
 }
 
+// buildBinary compiles the program in ./cmd/<name> for testing.
+func buildBinary(t *testing.T, name string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), name)
+	cmd := exec.Command("go", "build", "-o", path, "./cmd/"+name)
+	cmd.Dir = findProjectRoot(t)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("failed to build %s: %v\n%s", name, err, output)
+	}
+
+	return path
+}
+
 // runPushSwap runs the push-swap program with the given numbers and returns the output.
 func runPushSwap(t *testing.T, pushSwapPath string, numbers []string) []string {
 	t.Helper()
@@ -534,3 +556,1671 @@ func BenchmarkPushSwapMedium(b *testing.B) {
 		cmd.Run()
 	}
 }
+
+// runCheckerCompat runs the checker in -compat mode and returns its stdout,
+// stderr and exit code.
+func runCheckerCompat(t *testing.T, checkerPath, instructions string, numbers ...string) (string, string, int) {
+	t.Helper()
+
+	cmd := exec.Command(checkerPath, append([]string{"-compat", "--"}, numbers...)...)
+	cmd.Stdin = strings.NewReader(instructions)
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	err := cmd.Run()
+	if exitErr, ok := err.(*exec.ExitError); ok {
+		return stdout.String(), stderr.String(), exitErr.ExitCode()
+	} else if err != nil {
+		t.Fatalf("failed to run checker: %v", err)
+	}
+
+	return stdout.String(), stderr.String(), 0
+}
+
+func TestCheckerCompatMode(t *testing.T) {
+	_, checkerPath := buildBinaries(t)
+
+	tests := []struct {
+		name         string
+		instructions string
+		numbers      []string
+		wantStdout   string
+		wantStderr   string
+		wantCode     int
+	}{
+		{"sorted", "sa\n", []string{"2", "1", "3"}, "OK\n", "", 0},
+		{"already sorted", "", []string{"1 2 3"}, "OK\n", "", 0},
+		{"unsorted", "ra\n", []string{"2", "1", "3"}, "KO\n", "", 1},
+		{"B not empty", "pb\n", []string{"1", "2", "3"}, "KO\n", "", 1},
+		{"no numbers", "", nil, "", "", 0},
+		{"invalid number", "", []string{"1", "abc"}, "", "Error\n", 2},
+		{"duplicate number", "", []string{"1", "1"}, "", "Error\n", 2},
+		{"unknown instruction", "foo\n", []string{"2", "1"}, "", "Error\n", 2},
+		{"padded instruction", " sa\n", []string{"2", "1"}, "", "Error\n", 2},
+		{"blank line", "sa\n\n", []string{"2", "1"}, "", "Error\n", 2},
+		{"float", "", []string{"2", "1.5"}, "", "Error\n", 2},
+		{"above the 32-bit range", "", []string{"2147483648", "1"}, "", "Error\n", 2},
+		{"32-bit range", "sa\n", []string{"2147483647", "-2147483648"}, "OK\n", "", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stdout, stderr, code := runCheckerCompat(t, checkerPath, tt.instructions, tt.numbers...)
+
+			if stdout != tt.wantStdout {
+				t.Errorf("stdout = %q, want %q", stdout, tt.wantStdout)
+			}
+			if stderr != tt.wantStderr {
+				t.Errorf("stderr = %q, want %q", stderr, tt.wantStderr)
+			}
+			if code != tt.wantCode {
+				t.Errorf("exit code = %d, want %d", code, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestCheckerTrace(t *testing.T) {
+	_, checkerPath := buildBinaries(t)
+
+	t.Run("prints stacks after every instruction", func(t *testing.T) {
+		cmd := exec.Command(checkerPath, "-trace", "--", "2", "1", "3")
+		cmd.Stdin = strings.NewReader("pb\npa\nsa\n")
+
+		var stdout, stderr bytes.Buffer
+		cmd.Stdout = &stdout
+		cmd.Stderr = &stderr
+
+		if err := cmd.Run(); err != nil {
+			t.Fatalf("checker failed: %v, stderr: %s", err, stderr.String())
+		}
+
+		if strings.TrimSpace(stdout.String()) != "OK" {
+			t.Errorf("expected checker OK, got %q", stdout.String())
+		}
+
+		want := "[0] start\n\tA: { 2 1 3 }\n\tB: { }\n" +
+			"[1] pb\n\tA: { 1 3 }\n\tB: { 2 }\n" +
+			"[2] pa\n\tA: { 2 1 3 }\n\tB: { }\n" +
+			"[3] sa\n\tA: { 1 2 3 }\n\tB: { }\n"
+		if stderr.String() != want {
+			t.Errorf("trace = %q, want %q", stderr.String(), want)
+		}
+	})
+
+	t.Run("stops at the first invalid instruction", func(t *testing.T) {
+		traceFile := filepath.Join(t.TempDir(), "trace.txt")
+		cmd := exec.Command(checkerPath, "-compat", "-trace-stop", "-trace-file", traceFile, "--", "2", "1")
+		cmd.Stdin = strings.NewReader("pa\nsa\n")
+
+		var stdout bytes.Buffer
+		cmd.Stdout = &stdout
+		cmd.Run()
+
+		if strings.TrimSpace(stdout.String()) != "KO" {
+			t.Errorf("expected checker KO, got %q", stdout.String())
+		}
+
+		content, err := os.ReadFile(traceFile)
+		if err != nil {
+			t.Fatalf("failed to read trace file: %v", err)
+		}
+
+		if !strings.Contains(string(content), "instruction 1 (pa) could not be applied\nstopped\n") {
+			t.Errorf("expected trace to stop at instruction 1, got %q", content)
+		}
+		if strings.Contains(string(content), "[2] sa") {
+			t.Errorf("expected no steps after the invalid instruction, got %q", content)
+		}
+	})
+}
+
+func TestCheckerJSONReport(t *testing.T) {
+	_, checkerPath := buildBinaries(t)
+	tmp := t.TempDir()
+
+	okInst := filepath.Join(tmp, "ok.txt")
+	koInst := filepath.Join(tmp, "ko.txt")
+	badInst := filepath.Join(tmp, "bad.txt")
+	nums := filepath.Join(tmp, "nums.txt")
+
+	files := map[string]string{
+		okInst:  "sa\n",
+		koInst:  "pb\nra\npa\npa\n",
+		badInst: "sa\n\nfoo\nra\n",
+		nums:    "2 1 3",
+	}
+	for path, content := range files {
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("failed to write %s: %v", path, err)
+		}
+	}
+
+	cmd := exec.Command(
+		checkerPath, "-json",
+		"-files", okInst+","+nums,
+		"-files", koInst+","+nums,
+		"-files", badInst+","+nums,
+	)
+
+	var stdout bytes.Buffer
+	cmd.Stdout = &stdout
+	err := cmd.Run()
+
+	if exitErr, ok := err.(*exec.ExitError); !ok || exitErr.ExitCode() != 2 {
+		t.Errorf("expected exit code 2, got %v", err)
+	}
+
+	type report struct {
+		Verdict      string         `json:"verdict"`
+		Error        string         `json:"error"`
+		Count        int            `json:"count"`
+		OpCounts     map[string]int `json:"op_counts"`
+		FirstInvalid int            `json:"first_invalid"`
+		FinalA       []float64      `json:"final_a"`
+		FinalB       []float64      `json:"final_b"`
+	}
+
+	var reports []report
+	decoder := json.NewDecoder(&stdout)
+	for decoder.More() {
+		var r report
+		if err := decoder.Decode(&r); err != nil {
+			t.Fatalf("failed to decode report: %v", err)
+		}
+		reports = append(reports, r)
+	}
+
+	if len(reports) != 3 {
+		t.Fatalf("expected 3 reports, got %d", len(reports))
+	}
+
+	if r := reports[0]; r.Verdict != "OK" || r.Count != 1 || r.OpCounts["sa"] != 1 || r.FirstInvalid != -1 || r.FinalA != nil {
+		t.Errorf("unexpected OK report: %+v", r)
+	}
+
+	r := reports[1]
+	if r.Verdict != "KO" || r.Count != 4 || r.OpCounts["pa"] != 2 || r.FirstInvalid != 3 {
+		t.Errorf("unexpected KO report: %+v", r)
+	}
+	if fmt.Sprint(r.FinalA) != "[2 3 1]" || len(r.FinalB) != 0 {
+		t.Errorf("unexpected KO final stacks: A=%v B=%v", r.FinalA, r.FinalB)
+	}
+
+	// The blank line is skipped, so foo is the second instruction.
+	if r := reports[2]; r.Verdict != "Error" || !strings.Contains(r.Error, `line 3: unrecognised command "foo"`) || r.FirstInvalid != 1 {
+		t.Errorf("unexpected Error report: %+v", r)
+	}
+}
+
+func TestCheckerNoInput(t *testing.T) {
+	checkerPath := buildBinary(t, "checker")
+
+	for _, mode := range []string{"-json", "-grade"} {
+		cmd := exec.Command(checkerPath, mode)
+		cmd.Stdin = strings.NewReader("sa\n")
+
+		var stdout, stderr bytes.Buffer
+		cmd.Stdout = &stdout
+		cmd.Stderr = &stderr
+		cmd.Run()
+
+		if code := cmd.ProcessState.ExitCode(); code != 2 || stdout.Len() > 0 || !strings.Contains(stderr.String(), "Usage:") {
+			t.Errorf("checker %s without input = %q, stderr %q, exit code %d, want the usage and exit code 2", mode, stdout.String(), stderr.String(), code)
+		}
+	}
+}
+
+func TestCheckerGrade(t *testing.T) {
+	pushSwapPath, checkerPath := buildBinaries(t)
+
+	numbers := make([]string, 100)
+	for i := range numbers {
+		numbers[i] = fmt.Sprintf("%d", (i*37)%100)
+	}
+	instructions := strings.Join(runPushSwap(t, pushSwapPath, numbers), "\n")
+
+	runGrade := func(t *testing.T, extraArgs ...string) (string, int) {
+		t.Helper()
+
+		args := append([]string{"-grade"}, extraArgs...)
+		cmd := exec.Command(checkerPath, append(append(args, "--"), numbers...)...)
+		cmd.Stdin = strings.NewReader(instructions)
+
+		var stdout bytes.Buffer
+		cmd.Stdout = &stdout
+
+		err := cmd.Run()
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			return strings.TrimSpace(stdout.String()), exitErr.ExitCode()
+		} else if err != nil {
+			t.Fatalf("failed to run checker: %v", err)
+		}
+
+		return strings.TrimSpace(stdout.String()), 0
+	}
+
+	count := len(strings.Split(instructions, "\n"))
+
+	t.Run("default thresholds", func(t *testing.T) {
+		out, code := runGrade(t)
+		score := 0
+		for _, limit := range []int{700, 900, 1100, 1300, 1500} {
+			if count < limit {
+				score++
+			}
+		}
+
+		if want := fmt.Sprintf("OK %d %d/5", count, score); out != want || code != 0 {
+			t.Errorf("got %q (exit %d), want %q (exit 0)", out, code, want)
+		}
+	})
+
+	t.Run("custom thresholds from flag", func(t *testing.T) {
+		out, _ := runGrade(t, "-thresholds", fmt.Sprintf("100:%d,%d", count, count+1))
+
+		if want := fmt.Sprintf("OK %d 1/2", count); out != want {
+			t.Errorf("got %q, want %q", out, want)
+		}
+	})
+
+	t.Run("custom thresholds from file", func(t *testing.T) {
+		file := filepath.Join(t.TempDir(), "thresholds.txt")
+		content := fmt.Sprintf("# size:limits\n\n200:%d\n", count)
+		if err := os.WriteFile(file, []byte(content), 0644); err != nil {
+			t.Fatalf("failed to write thresholds file: %v", err)
+		}
+
+		out, _ := runGrade(t, "-thresholds-file", file)
+
+		if want := fmt.Sprintf("OK %d 0/1", count); out != want {
+			t.Errorf("got %q, want %q", out, want)
+		}
+	})
+
+	t.Run("size without thresholds is ungraded", func(t *testing.T) {
+		out, _ := runGrade(t, "-thresholds-file", os.DevNull)
+
+		if want := fmt.Sprintf("OK %d -", count); out != want {
+			t.Errorf("got %q, want %q", out, want)
+		}
+	})
+}
+
+func TestGen(t *testing.T) {
+	pushSwapPath, checkerPath := buildBinaries(t)
+	genPath := buildBinary(t, "gen")
+
+	runGen := func(t *testing.T, args ...string) []string {
+		t.Helper()
+
+		var stdout, stderr bytes.Buffer
+		cmd := exec.Command(genPath, args...)
+		cmd.Stdout = &stdout
+		cmd.Stderr = &stderr
+
+		if err := cmd.Run(); err != nil {
+			t.Fatalf("gen failed: %v, stderr: %s", err, stderr.String())
+		}
+
+		return strings.Fields(stdout.String())
+	}
+
+	tests := []struct {
+		name      string
+		args      []string
+		skipSolve bool
+	}{
+		{name: "unique ints", args: []string{"-min", "-50", "-max", "50", "100"}},
+		{name: "uniform floats", args: []string{"-float", "200"}},
+		// TurkAlgorithm can loop forever when a value repeats many times, so
+		// only the generator output is checked for this distribution.
+		{name: "duplicates", args: []string{"-dist", "duplicates", "-dup-percent", "30", "100"}, skipSolve: true},
+		{name: "nearly sorted", args: []string{"-dist", "nearly-sorted", "-position", "bottom", "100"}},
+		{name: "tiny", args: []string{"-dist", "tiny", "100"}},
+		{name: "massive", args: []string{"-dist", "massive", "100"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args := append([]string{"-seed", "7"}, tt.args...)
+			numbers := runGen(t, args...)
+
+			if want := tt.args[len(tt.args)-1]; fmt.Sprint(len(numbers)) != want {
+				t.Fatalf("got %d numbers, want %s", len(numbers), want)
+			}
+			if again := runGen(t, args...); strings.Join(again, " ") != strings.Join(numbers, " ") {
+				t.Errorf("same seed produced different output")
+			}
+
+			if tt.skipSolve {
+				return
+			}
+
+			cmd := exec.Command(pushSwapPath)
+			cmd.Stdin = strings.NewReader(strings.Join(numbers, " "))
+			instructions, err := cmd.Output()
+			if err != nil {
+				t.Fatalf("push-swap failed: %v", err)
+			}
+
+			checker := exec.Command(checkerPath, append([]string{"--"}, numbers...)...)
+			checker.Stdin = bytes.NewReader(instructions)
+			out, err := checker.Output()
+			if err != nil {
+				t.Fatalf("checker failed: %v", err)
+			}
+
+			if strings.TrimSpace(string(out)) != "OK" {
+				t.Errorf("expected checker OK, got %q", out)
+			}
+		})
+	}
+
+	t.Run("too many unique ints", func(t *testing.T) {
+		cmd := exec.Command(genPath, "-min", "1", "-max", "5", "6")
+		if err := cmd.Run(); err == nil {
+			t.Errorf("expected gen to fail when the range is too small")
+		}
+	})
+
+	t.Run("full int range with duplicates", func(t *testing.T) {
+		numbers := runGen(t, "-allow-duplicates", "-min", fmt.Sprint(math.MinInt64), "-max", fmt.Sprint(math.MaxInt64), "3")
+		if len(numbers) != 3 {
+			t.Errorf("got %d numbers, want 3", len(numbers))
+		}
+	})
+
+	invalid := [][]string{
+		{"-dist", "duplicates", "-dup-percent", "150", "5"},
+		{"-dist", "duplicates", "-dup-percent", "-10", "5"},
+		{"-dist", "nearly-sorted", "-cluster", "-1", "5"},
+		// Options of other distributions.
+		{"-dup-percent", "10", "5"},
+		{"-dist", "tiny", "-float", "5"},
+		{"-dist", "massive", "-min", "0", "5"},
+	}
+
+	for _, args := range invalid {
+		t.Run(strings.Join(args, " "), func(t *testing.T) {
+			out, err := exec.Command(genPath, args...).CombinedOutput()
+			if exitErr, ok := err.(*exec.ExitError); !ok || exitErr.ExitCode() != 1 || !strings.Contains(string(out), "ERROR") {
+				t.Errorf("gen %v = %q, %v, want an error", args, out, err)
+			}
+		})
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	evaluatePath := buildBinary(t, "evaluate")
+	dumpDir := filepath.Join(t.TempDir(), "worst")
+
+	run := func(t *testing.T) string {
+		t.Helper()
+
+		cmd := exec.Command(evaluatePath, "-trials", "10", "-sizes", "3,5,20", "-seed", "4", "-dump", dumpDir)
+
+		var stdout, stderr bytes.Buffer
+		cmd.Stdout = &stdout
+		cmd.Stderr = &stderr
+
+		if err := cmd.Run(); err != nil {
+			t.Fatalf("evaluate failed: %v, stderr: %s", err, stderr.String())
+		}
+
+		return stdout.String()
+	}
+
+	output := run(t)
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+
+	if len(lines) != 4 {
+		t.Fatalf("expected a header and 3 rows, got %q", output)
+	}
+
+	for i, size := range []string{"3", "5", "20"} {
+		fields := strings.Fields(lines[i+1])
+
+		if fields[0] != size || fields[1] != "10" {
+			t.Errorf("row %d = %q, want size %s with 10 trials", i, lines[i+1], size)
+		}
+		if fields[6] != "0" {
+			t.Errorf("row %d reports %s failures", i, fields[6])
+		}
+
+		content, err := os.ReadFile(filepath.Join(dumpDir, "worst-"+size+".txt"))
+		if err != nil {
+			t.Fatalf("failed to read worst input: %v", err)
+		}
+		if got := len(strings.Fields(string(content))); fmt.Sprint(got) != size {
+			t.Errorf("worst input for size %s has %d numbers", size, got)
+		}
+	}
+
+	// The timing column varies between runs, everything else must not.
+	stripTimes := func(s string) string {
+		var rows []string
+		for _, line := range strings.Split(s, "\n") {
+			fields := strings.Fields(line)
+			if len(fields) > 7 {
+				fields = append(fields[:7], fields[len(fields)-1])
+			}
+			rows = append(rows, strings.Join(fields, " "))
+		}
+		return strings.Join(rows, "\n")
+	}
+
+	if again := run(t); stripTimes(again) != stripTimes(output) {
+		t.Errorf("same seed produced different statistics:\n%s\n%s", output, again)
+	}
+
+	// With a single trial the worst seed is the only one drawn. -seed 1 at
+	// size 5 must not draw the same seeds as -seed 3 at size 3.
+	trialSeed := func(seed, size string) string {
+		out, err := exec.Command(evaluatePath, "-trials", "1", "-sizes", size, "-seed", seed).Output()
+		if err != nil {
+			t.Fatalf("evaluate failed: %v", err)
+		}
+
+		fields := strings.Fields(string(out))
+		return fields[len(fields)-1]
+	}
+
+	if a, b := trialSeed("1", "5"), trialSeed("3", "3"); a == b {
+		t.Errorf("-seed 1 -sizes 5 and -seed 3 -sizes 3 both drew the trial seed %s", a)
+	}
+}
+
+func TestVisualize(t *testing.T) {
+	visualizePath := buildBinary(t, "visualize")
+
+	cmd := exec.Command(visualizePath, "-no-controls", "-delay", "1ms", "-width", "3", "--", "3", "1", "2")
+	cmd.Stdin = strings.NewReader("pb\nsa\npa\npa\nsa\n")
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		t.Fatalf("visualize failed: %v, stderr: %s", err, stderr.String())
+	}
+
+	// Drop the escape codes hiding and showing the cursor around the frames.
+	output := strings.TrimSuffix(strings.TrimPrefix(stdout.String(), "\x1b[?25l"), "\x1b[?25h")
+	frames := strings.Split(output, "\x1b[H\x1b[2J")
+	if len(frames) != 7 {
+		t.Fatalf("expected 6 frames, got %d: %q", len(frames)-1, stdout.String())
+	}
+
+	// Bars are proportional to the rank of every value: 1, 2 and 3 out of 3.
+	tests := []struct {
+		frame int
+		want  string
+	}{
+		{0, "step 0/5  start               playing  delay 1ms\nA     B\n███ |    \n█   |    \n██  |    \n"},
+		{3, "step 3/5  pa                  playing  delay 1ms\nA     B\n███ |    \n██  |    \n█   |    \n"},
+		{4, "step 4/5  pa (not applied)    playing  delay 1ms\nA     B\n███ |    \n██  |    \n█   |    \n"},
+		{5, "step 5/5  sa                  paused   delay 1ms\nA     B\n██  |    \n███ |    \n█   |    \n"},
+	}
+
+	for _, tt := range tests {
+		if got := frames[tt.frame+1]; got != tt.want {
+			t.Errorf("frame %d = %q, want %q", tt.frame, got, tt.want)
+		}
+	}
+}
+
+func TestVisualizeNumberFlags(t *testing.T) {
+	visualizePath := buildBinary(t, "visualize")
+
+	strs := filepath.Join(t.TempDir(), "strs.json")
+	if err := os.WriteFile(strs, []byte(`["c", "a", "b"]`), 0644); err != nil {
+		t.Fatalf("failed to write numbers: %v", err)
+	}
+
+	// Every input has the ranks of 3 1 2.
+	want := "step 0/0  start               playing  delay 1ms\nA     B\n███ |    \n█   |    \n██  |    \n"
+	for _, args := range [][]string{
+		{"-5", "-7", "-6"},
+		{"-type", "int", "-5 -7 -6"},
+		{"-type", "string", "-numbers", strs},
+	} {
+		cmd := exec.Command(visualizePath, append([]string{"-no-controls", "-delay", "1ms", "-width", "3"}, args...)...)
+		cmd.Stdin = strings.NewReader("")
+
+		out, err := cmd.Output()
+		if err != nil {
+			t.Errorf("visualize %q failed: %v", args, err)
+			continue
+		}
+
+		if !strings.Contains(string(out), want) {
+			t.Errorf("visualize %q = %q, want the frame %q", args, out, want)
+		}
+	}
+}
+
+func TestVisualizeExport(t *testing.T) {
+	visualizePath := buildBinary(t, "visualize")
+	dir := t.TempDir()
+	htmlFile := filepath.Join(dir, "replay.html")
+	svgDir := filepath.Join(dir, "frames")
+
+	cmd := exec.Command(visualizePath, "-html", htmlFile, "-svg", svgDir, "--", "3", "1", "2")
+	cmd.Stdin = strings.NewReader("pb\nsa\npa\n")
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		t.Fatalf("visualize failed: %v, stderr: %s", err, stderr.String())
+	}
+
+	if stdout.Len() != 0 {
+		t.Errorf("expected no animation when exporting, got %q", stdout.String())
+	}
+
+	page, err := os.ReadFile(htmlFile)
+	if err != nil {
+		t.Fatalf("reading HTML export: %v", err)
+	}
+
+	if !strings.Contains(string(page), `"instructions":["pb","sa","pa"]`) {
+		t.Errorf("HTML export does not embed the instructions")
+	}
+
+	frames, err := filepath.Glob(filepath.Join(svgDir, "frame-*.svg"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The initial state plus one frame per instruction.
+	if len(frames) != 4 {
+		t.Errorf("expected 4 SVG frames, got %v", frames)
+	}
+
+	last, err := os.ReadFile(filepath.Join(svgDir, "frame-0003.svg"))
+	if err != nil {
+		t.Fatalf("reading last frame: %v", err)
+	}
+
+	if !strings.Contains(string(last), "step 3: pa") {
+		t.Errorf("last frame has no caption for step 3: %s", last)
+	}
+}
+
+func TestVisualizeGIF(t *testing.T) {
+	visualizePath := buildBinary(t, "visualize")
+	dir := t.TempDir()
+	gifFile := filepath.Join(dir, "replay.gif")
+	pngDir := filepath.Join(dir, "frames")
+
+	cmd := exec.Command(visualizePath, "-gif", gifFile, "-gif-max-frames", "3", "-png", pngDir, "--", "3", "1", "2")
+	cmd.Stdin = strings.NewReader("pb\nsa\npa\nra\n")
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		t.Fatalf("visualize failed: %v, stderr: %s", err, stderr.String())
+	}
+
+	f, err := os.Open(gifFile)
+	if err != nil {
+		t.Fatalf("opening GIF export: %v", err)
+	}
+	defer f.Close()
+
+	anim, err := gif.DecodeAll(f)
+	if err != nil {
+		t.Fatalf("decoding GIF export: %v", err)
+	}
+
+	// Four instructions in at most three frames: steps 0, 2 and 4.
+	if len(anim.Image) != 3 {
+		t.Errorf("expected 3 GIF frames, got %d", len(anim.Image))
+	}
+
+	frames, err := filepath.Glob(filepath.Join(pngDir, "frame-*.png"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(frames) != 5 {
+		t.Errorf("expected 5 PNG frames, got %v", frames)
+	}
+}
+
+func TestPushswapd(t *testing.T) {
+	serverPath := buildBinary(t, "pushswapd")
+
+	cmd := exec.Command(serverPath, "-addr", "127.0.0.1:0")
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := cmd.Start(); err != nil {
+		t.Fatalf("starting pushswapd: %v", err)
+	}
+	defer cmd.Process.Kill()
+
+	// The first log line holds the address the server listens on.
+	logs := bufio.NewScanner(stderr)
+	if !logs.Scan() {
+		t.Fatalf("pushswapd exited without logging its address")
+	}
+
+	_, addr, found := strings.Cut(logs.Text(), "listening on ")
+	if !found {
+		t.Fatalf("unexpected log line %q", logs.Text())
+	}
+
+	resp, err := http.Post("http://"+addr+"/solve", "application/json", strings.NewReader(`{"numbers": [3, 1, 2]}`))
+	if err != nil {
+		t.Fatalf("POST /solve: %v", err)
+	}
+	defer resp.Body.Close()
+
+	var solved struct {
+		Instructions []string `json:"instructions"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&solved); err != nil {
+		t.Fatalf("decoding /solve response: %v", err)
+	}
+
+	if resp.StatusCode != http.StatusOK || len(solved.Instructions) == 0 {
+		t.Errorf("POST /solve = %d %v, want instructions", resp.StatusCode, solved.Instructions)
+	}
+
+	if err := cmd.Process.Signal(os.Interrupt); err != nil {
+		t.Fatal(err)
+	}
+
+	for logs.Scan() {
+	}
+
+	if err := cmd.Wait(); err != nil {
+		t.Errorf("pushswapd did not shut down cleanly: %v", err)
+	}
+}
+
+func TestPlay(t *testing.T) {
+	playPath := buildBinary(t, "play")
+	transcript := filepath.Join(t.TempDir(), "transcript.txt")
+
+	script := strings.Join([]string{"hint", "pb", "pa pa", "undo 2", "bogus", "sa", "save " + transcript, "quit"}, "\n")
+	cmd := exec.Command(playPath, "2", "1", "3")
+	cmd.Stdin = strings.NewReader(script)
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		t.Fatalf("play failed: %v, stderr: %s", err, stderr.String())
+	}
+
+	output := stdout.String()
+	for _, want := range []string{
+		"> hint: sa\n",
+		"> pa could not be applied\n",
+		"error: unknown command \"bogus\"",
+		"A | B\n1 |\n2 |\n3 |\n1 instructions, sorted\n",
+		"saved 1 instructions to " + transcript,
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("output does not contain %q:\n%s", want, output)
+		}
+	}
+
+	saved, err := os.ReadFile(transcript)
+	if err != nil {
+		t.Fatalf("reading transcript: %v", err)
+	}
+
+	if string(saved) != "sa\n" {
+		t.Errorf("transcript = %q, want %q", saved, "sa\n")
+	}
+}
+
+func TestPlayNumberFlags(t *testing.T) {
+	playPath := buildBinary(t, "play")
+
+	versions := filepath.Join(t.TempDir(), "versions.json")
+	if err := os.WriteFile(versions, []byte(`["1.10", "1.9", "2"]`), 0644); err != nil {
+		t.Fatalf("failed to write numbers: %v", err)
+	}
+
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{[]string{"-2", "-1"}, "A  | B\n-2 |\n-1 |\n0 instructions, sorted\n"},
+		{[]string{"-type", "version", "-numbers", versions}, "A    | B\n1.10 |\n1.9  |\n2    |\n0 instructions\n"},
+	}
+
+	for _, tt := range tests {
+		cmd := exec.Command(playPath, tt.args...)
+		cmd.Stdin = strings.NewReader("quit\n")
+
+		out, err := cmd.Output()
+		if err != nil {
+			t.Errorf("play %q failed: %v", tt.args, err)
+			continue
+		}
+
+		if !strings.Contains(string(out), tt.want) {
+			t.Errorf("play %q = %q, want %q", tt.args, out, tt.want)
+		}
+	}
+}
+
+func TestPushSwapExplain(t *testing.T) {
+	pushSwapPath := buildBinary(t, "push-swap")
+	checkerPath := buildBinary(t, "checker")
+	numbers := []string{"8", "3", "-4", "12", "0", "7", "1"}
+
+	explain := func(format string) string {
+		cmd := exec.Command(pushSwapPath, "-explain", format)
+		cmd.Stdin = strings.NewReader(strings.Join(numbers, " "))
+
+		var stdout, stderr bytes.Buffer
+		cmd.Stdout = &stdout
+		cmd.Stderr = &stderr
+
+		if err := cmd.Run(); err != nil {
+			t.Fatalf("push-swap -explain %s failed: %v, stderr: %s", format, err, stderr.String())
+		}
+
+		return stdout.String()
+	}
+
+	plain := runPushSwap(t, pushSwapPath, numbers)
+
+	// The commented instructions are the plain ones with comment lines added.
+	commented := explain("comments")
+	var instructions []string
+	for _, line := range strings.Split(strings.TrimSpace(commented), "\n") {
+		if !strings.HasPrefix(line, "# ") {
+			instructions = append(instructions, line)
+		}
+	}
+
+	if strings.Join(instructions, " ") != strings.Join(plain, " ") {
+		t.Errorf("commented instructions %v differ from %v", instructions, plain)
+	}
+
+	if !strings.Contains(commented, "# push to B: ") || !strings.Contains(commented, "# sort last 3\n") {
+		t.Errorf("missing comments in:\n%s", commented)
+	}
+
+	// The checker skips the comments.
+	result, err := runChecker(t, checkerPath, strings.Split(commented, "\n"), numbers)
+	if err != nil || result != "OK" {
+		t.Errorf("checker on commented instructions = %q, %v, want OK", result, err)
+	}
+
+	var explained struct {
+		Instructions []string `json:"instructions"`
+		Steps        []struct {
+			Phase        string   `json:"phase"`
+			Instructions []string `json:"instructions"`
+			Move         *struct {
+				Value float64 `json:"value"`
+				Route string  `json:"route"`
+			} `json:"move"`
+		} `json:"steps"`
+	}
+
+	if err := json.Unmarshal([]byte(explain("json")), &explained); err != nil {
+		t.Fatalf("decoding -explain json output: %v", err)
+	}
+
+	if strings.Join(explained.Instructions, " ") != strings.Join(plain, " ") {
+		t.Errorf("JSON instructions %v differ from %v", explained.Instructions, plain)
+	}
+
+	moves := 0
+	for _, step := range explained.Steps {
+		if step.Move != nil {
+			moves++
+		}
+	}
+
+	// Every value but the last 3 is pushed back to A after a move, and all of
+	// them but the first 2 were pushed to B after a move too.
+	if want := 2*(len(numbers)-3) - 2; moves != want {
+		t.Errorf("got %d explained moves, want %d", moves, want)
+	}
+}
+
+func TestPushSwapStats(t *testing.T) {
+	pushSwapPath := buildBinary(t, "push-swap")
+	numbers := []string{"8", "3", "-4", "12", "0", "7", "1", "5"}
+
+	cmd := exec.Command(pushSwapPath, "-stats")
+	cmd.Stdin = strings.NewReader(strings.Join(numbers, " "))
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		t.Fatalf("push-swap -stats failed: %v, stderr: %s", err, stderr.String())
+	}
+
+	// The instructions are unchanged on stdout.
+	instructions := strings.Fields(stdout.String())
+	if plain := runPushSwap(t, pushSwapPath, numbers); strings.Join(instructions, " ") != strings.Join(plain, " ") {
+		t.Errorf("instructions with -stats %v differ from %v", instructions, plain)
+	}
+
+	phases := map[string]int{}
+	for _, line := range strings.Split(stderr.String(), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 3 || fields[len(fields)-2] == "instructions" {
+			continue
+		}
+
+		count, err := strconv.Atoi(fields[len(fields)-2])
+		if err != nil {
+			continue
+		}
+
+		phases[strings.Join(fields[:len(fields)-2], " ")] = count
+	}
+
+	sum := 0
+	for _, phase := range []string{"initial push", "push to B", "sort last 3", "push to A", "final rotation"} {
+		count, ok := phases[phase]
+		if !ok {
+			t.Fatalf("no stats for phase %q in:\n%s", phase, stderr.String())
+		}
+
+		sum += count
+	}
+
+	if phases["total"] != len(instructions) || sum != len(instructions) {
+		t.Errorf("stats total %d and phase sum %d, want %d:\n%s", phases["total"], sum, len(instructions), stderr.String())
+	}
+
+	if !strings.Contains(stderr.String(), "move cost  push to B  push to A") {
+		t.Errorf("no move cost histogram in:\n%s", stderr.String())
+	}
+}
+
+func TestJobs(t *testing.T) {
+	pushSwapPath, checkerPath := buildBinaries(t)
+	tmp := t.TempDir()
+
+	// Inputs of decreasing size finish in reverse order when run at once.
+	var inputs []string
+	for i := range 8 {
+		size := 200 - 20*i
+		numbers := make([]string, size)
+		for j := range numbers {
+			numbers[j] = strconv.Itoa((j*37 + i) % size)
+		}
+
+		input := filepath.Join(tmp, fmt.Sprintf("input%d.txt", i))
+		if err := os.WriteFile(input, []byte(strings.Join(numbers, " ")), 0644); err != nil {
+			t.Fatalf("failed to write %s: %v", input, err)
+		}
+
+		inputs = append(inputs, input)
+	}
+
+	missing := filepath.Join(tmp, "missing.txt")
+	inputs = append(inputs[:3], append([]string{missing}, inputs[3:]...)...)
+
+	run := func(t *testing.T, path string, args ...string) (string, string) {
+		t.Helper()
+
+		cmd := exec.Command(path, args...)
+
+		var stdout, stderr bytes.Buffer
+		cmd.Stdout = &stdout
+		cmd.Stderr = &stderr
+		cmd.Run()
+
+		// Log lines start with a timestamp.
+		var errors []string
+		for _, line := range strings.Split(strings.TrimSpace(stderr.String()), "\n") {
+			if _, msg, ok := strings.Cut(line, "ERROR: "); ok {
+				errors = append(errors, msg)
+			} else {
+				errors = append(errors, line)
+			}
+		}
+
+		return stdout.String(), strings.Join(errors, "\n")
+	}
+
+	t.Run("push-swap writes results in input order", func(t *testing.T) {
+		var args []string
+		for _, input := range inputs {
+			args = append(args, "-files", input+",-")
+		}
+
+		wantOut, wantErr := run(t, pushSwapPath, append([]string{"-jobs", "1"}, args...)...)
+		gotOut, gotErr := run(t, pushSwapPath, append([]string{"-jobs", "4"}, args...)...)
+
+		if gotOut != wantOut {
+			t.Errorf("output with -jobs 4 differs from -jobs 1")
+		}
+		if gotErr != wantErr || !strings.Contains(gotErr, "missing.txt") {
+			t.Errorf("errors with -jobs 4 = %q, want %q", gotErr, wantErr)
+		}
+	})
+
+	t.Run("checker reports results in input order", func(t *testing.T) {
+		var args []string
+		for i, input := range inputs {
+			output := input + ".output"
+			if input != missing {
+				if _, err := exec.Command(pushSwapPath, "-files", input+","+output).CombinedOutput(); err != nil {
+					t.Fatalf("push-swap failed on %s: %v", input, err)
+				}
+			}
+
+			// Every other pair is checked against the wrong numbers.
+			numbers := input
+			if i%2 == 1 && input != missing {
+				numbers = inputs[0]
+			}
+
+			args = append(args, "-files", output+","+numbers)
+		}
+
+		// Elapsed times in the JSON reports differ between runs.
+		elapsed := regexp.MustCompile(`"elapsed_ns":\d+`)
+
+		for _, mode := range [][]string{{}, {"-compat"}, {"-grade"}, {"-json"}, {"-trace"}} {
+			wantOut, wantErr := run(t, checkerPath, append(append(mode, "-jobs", "1"), args...)...)
+			gotOut, gotErr := run(t, checkerPath, append(append(mode, "-jobs", "4"), args...)...)
+			wantOut = elapsed.ReplaceAllString(wantOut, "")
+			gotOut = elapsed.ReplaceAllString(gotOut, "")
+
+			if gotOut != wantOut || strings.Count(gotOut, "OK")+strings.Count(gotOut, "KO") < 2 {
+				t.Errorf("checker %v output with -jobs 4 = %q, want %q", mode, gotOut, wantOut)
+			}
+
+			if gotErr != wantErr {
+				t.Errorf("checker %v errors with -jobs 4 differ from -jobs 1", mode)
+			}
+		}
+	})
+}
+
+func TestDirectoryInputs(t *testing.T) {
+	pushSwapPath, checkerPath := buildBinaries(t)
+	tmp := t.TempDir()
+
+	inputDir := filepath.Join(tmp, "corpus")
+	if err := os.MkdirAll(filepath.Join(inputDir, "skipped.nums"), 0755); err != nil {
+		t.Fatalf("failed to create directory: %v", err)
+	}
+
+	inputs := map[string]string{
+		"a.nums": "3 2 1",
+		"b.nums": "5 -1 4 8 0 2",
+		"c.nums": "10 9 8 7 6 5 4 3 2 1",
+		"d.txt":  "2 1",
+	}
+	for name, numbers := range inputs {
+		if err := os.WriteFile(filepath.Join(inputDir, name), []byte(numbers), 0644); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+	}
+
+	run := func(t *testing.T, path string, args ...string) (string, string, int) {
+		t.Helper()
+
+		cmd := exec.Command(path, args...)
+
+		var stdout, stderr bytes.Buffer
+		cmd.Stdout = &stdout
+		cmd.Stderr = &stderr
+		cmd.Run()
+
+		return stdout.String(), stderr.String(), cmd.ProcessState.ExitCode()
+	}
+
+	t.Run("input and output directories", func(t *testing.T) {
+		outputDir := filepath.Join(tmp, "solved")
+
+		_, stderr, code := run(t, pushSwapPath, "-input-dir", inputDir, "-output-dir", outputDir, "-jobs", "2")
+		if code != 0 {
+			t.Fatalf("push-swap -input-dir failed with code %d: %s", code, stderr)
+		}
+
+		for _, name := range []string{"a.ops", "b.ops", "c.ops"} {
+			if _, err := os.Stat(filepath.Join(outputDir, name)); err != nil {
+				t.Errorf("expected %s to be written: %v", name, err)
+			}
+		}
+		if _, err := os.Stat(filepath.Join(outputDir, "d.ops")); err == nil {
+			t.Errorf("expected d.txt to be skipped")
+		}
+
+		stdout, stderr, code := run(t, checkerPath, "-compat", "-manifest", inputDir, "-ops-dir", outputDir)
+		if stdout != "OK\nOK\nOK\n" || code != 0 {
+			t.Errorf("checker -manifest = %q with code %d, stderr %q", stdout, code, stderr)
+		}
+
+		// An instructions file without numbers is reported on its own.
+		if err := os.WriteFile(filepath.Join(outputDir, "e.ops"), []byte("sa\n"), 0644); err != nil {
+			t.Fatalf("failed to write e.ops: %v", err)
+		}
+
+		stdout, stderr, code = run(t, checkerPath, "-json", "-manifest", inputDir, "-ops-dir", outputDir)
+		lines := strings.Split(strings.TrimSpace(stdout), "\n")
+		if len(lines) != 4 || code != 2 {
+			t.Fatalf("checker -json -manifest printed %d reports with code %d, want 4 with code 2: %s", len(lines), code, stderr)
+		}
+
+		var rep struct {
+			NumbersFile string `json:"numbers_file"`
+			Verdict     string `json:"verdict"`
+		}
+		if err := json.Unmarshal([]byte(lines[3]), &rep); err != nil {
+			t.Fatalf("invalid report %q: %v", lines[3], err)
+		}
+		if rep.Verdict != "Error" || filepath.Base(rep.NumbersFile) != "e.nums" {
+			t.Errorf("report for e.ops = %+v, want an error for e.nums", rep)
+		}
+	})
+
+	t.Run("glob patterns", func(t *testing.T) {
+		_, stderr, code := run(t, pushSwapPath, "-glob", filepath.Join(inputDir, "[ab].nums"), "-glob", filepath.Join(inputDir, "*.txt"))
+		if code != 0 {
+			t.Fatalf("push-swap -glob failed with code %d: %s", code, stderr)
+		}
+
+		for name, want := range map[string]bool{"a.ops": true, "b.ops": true, "c.ops": false, "d.ops": true} {
+			_, err := os.Stat(filepath.Join(inputDir, name))
+			if got := err == nil; got != want {
+				t.Errorf("%s written = %v, want %v", name, got, want)
+			}
+		}
+
+		_, stderr, code = run(t, pushSwapPath, "-glob", filepath.Join(inputDir, "*.none"))
+		if code == 0 || !strings.Contains(stderr, "no input files match") {
+			t.Errorf("expected an error for a pattern without matches, got code %d: %s", code, stderr)
+		}
+	})
+}
+
+func TestPushSwapArguments(t *testing.T) {
+	pushSwapPath, checkerPath := buildBinaries(t)
+	numbers := []string{"5", "-1", "4", "8", "0", "2"}
+	want := runPushSwap(t, pushSwapPath, numbers)
+
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{"separate arguments", append([]string{"--"}, numbers...)},
+		{"one quoted argument", []string{"--", strings.Join(numbers, " ")}},
+		{"mixed", []string{"--", "5 -1", "4", "8 0 2"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := exec.Command(pushSwapPath, tt.args...)
+			cmd.Stdin = strings.NewReader("9 8 7")
+
+			var stdout, stderr bytes.Buffer
+			cmd.Stdout = &stdout
+			cmd.Stderr = &stderr
+
+			if err := cmd.Run(); err != nil {
+				t.Fatalf("push-swap failed: %v, stderr: %s", err, stderr.String())
+			}
+
+			got := strings.Fields(stdout.String())
+			if strings.Join(got, " ") != strings.Join(want, " ") {
+				t.Errorf("instructions = %v, want %v as for stdin", got, want)
+			}
+
+			status, err := runChecker(t, checkerPath, got, numbers)
+			if err != nil || status != "OK" {
+				t.Errorf("checker = %q, %v, want OK", status, err)
+			}
+		})
+	}
+
+	t.Run("negative first number", func(t *testing.T) {
+		args := []string{"-jobs", "-1", "-5", "3", "2"}
+		out, err := exec.Command(pushSwapPath, args...).Output()
+		if err != nil {
+			t.Fatalf("push-swap %v failed: %v", args, err)
+		}
+
+		checker := exec.Command(checkerPath, "-compat", "-5", "3", "2")
+		checker.Stdin = bytes.NewReader(out)
+		if status, err := checker.Output(); err != nil || string(status) != "OK\n" {
+			t.Errorf("checker -compat -5 3 2 = %q, %v, want OK", status, err)
+		}
+	})
+
+	// Like the original, invalid numbers are reported as Error only.
+	for _, args := range [][]string{{"3", "x", "1"}, {"3", "1", "3"}, {"-5", "2", "-5"}, {"1 NaN"}} {
+		cmd := exec.Command(pushSwapPath, args...)
+
+		var stdout, stderr bytes.Buffer
+		cmd.Stdout = &stdout
+		cmd.Stderr = &stderr
+		err := cmd.Run()
+
+		if cmd.ProcessState.ExitCode() != 1 || stderr.String() != "Error\n" || stdout.Len() > 0 {
+			t.Errorf("push-swap %q = %q, stderr %q, %v, want Error on stderr and exit code 1", args, stdout.String(), stderr.String(), err)
+		}
+	}
+}
+
+func TestNumberModes(t *testing.T) {
+	pushSwapPath, checkerPath := buildBinaries(t)
+
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr string
+	}{
+		{"finite by default", []string{"--", "1", "NaN"}, `error parsing "NaN" at line 1, column 3: not a finite decimal number`},
+		{"int32 range", []string{"-number-mode", "int32", "--", "1", "2147483648"}, "outside the 32-bit integer range"},
+		{"int32 floats", []string{"-number-mode", "int32", "--", "1", "2.5"}, `error parsing "2.5" at line 1, column 3: not an integer`},
+		{"any", []string{"-number-mode", "any", "--", "2", "0x1p-2"}, ""},
+		{"int32 valid", []string{"-number-mode", "int32", "--", "-2147483648", "2147483647", "0"}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Numbers on stdin get detailed errors, those on the command
+			// line only Error.
+			sep := slices.Index(tt.args, "--")
+			cmd := exec.Command(pushSwapPath, tt.args[:sep]...)
+			cmd.Stdin = strings.NewReader(strings.Join(tt.args[sep+1:], " "))
+
+			var stdout, stderr bytes.Buffer
+			cmd.Stdout = &stdout
+			cmd.Stderr = &stderr
+			cmd.Run()
+
+			if tt.wantErr != "" {
+				if !strings.Contains(stderr.String(), tt.wantErr) {
+					t.Errorf("push-swap stderr = %q, want %q", stderr.String(), tt.wantErr)
+				}
+
+				// The checker follows the same rules.
+				checker := exec.Command(checkerPath, append([]string{"-compat"}, tt.args...)...)
+				if err := checker.Run(); checker.ProcessState.ExitCode() != 2 {
+					t.Errorf("checker -compat exit code = %d (%v), want 2", checker.ProcessState.ExitCode(), err)
+				}
+
+				return
+			}
+
+			if stderr.Len() > 0 {
+				t.Fatalf("push-swap failed: %s", stderr.String())
+			}
+
+			checker := exec.Command(checkerPath, append([]string{"-compat"}, tt.args...)...)
+			checker.Stdin = &stdout
+			out, _ := checker.Output()
+			if string(out) != "OK\n" {
+				t.Errorf("checker = %q, want OK", out)
+			}
+		})
+	}
+
+	out, err := exec.Command(pushSwapPath, "-number-mode", "int64", "1").CombinedOutput()
+	if err == nil || !strings.Contains(string(out), "unknown number mode") {
+		t.Errorf("expected an error for an unknown mode, got %q, %v", out, err)
+	}
+
+	for _, path := range []string{pushSwapPath, checkerPath} {
+		out, err := exec.Command(path, "-type", "int", "-number-mode", "int32", "1").CombinedOutput()
+		if err == nil || !strings.Contains(string(out), "only apply to -type float") {
+			t.Errorf("%s: expected -number-mode to be rejected with -type int, got %q, %v", filepath.Base(path), out, err)
+		}
+	}
+}
+
+func TestDuplicateEquality(t *testing.T) {
+	pushSwapPath, checkerPath := buildBinaries(t)
+
+	pushSwap := exec.Command(pushSwapPath)
+	pushSwap.Stdin = strings.NewReader("3 0 0.5 -0")
+	out, _ := pushSwap.CombinedOutput()
+	if !strings.Contains(string(out), `duplicate number "-0" at index 3 (line 1, column 9), first given as "0" at index 1`) {
+		t.Errorf("expected -0 to duplicate 0, got %q", out)
+	}
+
+	out, err := exec.Command(pushSwapPath, "-equality", "bitwise", "--", "3", "0", "0.5", "-0").CombinedOutput()
+	if err != nil || strings.Contains(string(out), "ERROR") {
+		t.Errorf("expected -0 and 0 to differ bitwise, got %q, %v", out, err)
+	}
+
+	for _, equality := range []string{"numeric", "bitwise"} {
+		checker := exec.Command(checkerPath, "-compat", "-number-mode", "finite", "-equality", equality, "--", "1", "0", "-0")
+		checker.Stdin = strings.NewReader("ra\n")
+
+		want := "OK\n"
+		if equality == "numeric" {
+			want = ""
+		}
+
+		if status, _ := checker.Output(); string(status) != want {
+			t.Errorf("checker -equality %s = %q, want %q", equality, status, want)
+		}
+	}
+}
+
+func TestNumberErrorLocation(t *testing.T) {
+	pushSwapPath, checkerPath := buildBinaries(t)
+
+	checker := exec.Command(checkerPath, "--", "4", "2", "abc", "1")
+	checker.Stdin = strings.NewReader("")
+	out, _ := checker.CombinedOutput()
+	if !strings.Contains(string(out), "\n4 2 abc 1\n    ^^^\n") {
+		t.Errorf("expected the invalid token to be highlighted, got:\n%s", out)
+	}
+
+	pushSwap := exec.Command(pushSwapPath)
+	pushSwap.Stdin = strings.NewReader("4 2\nabc 1\n")
+	out, _ = pushSwap.CombinedOutput()
+	if !strings.Contains(string(out), `error parsing "abc" at line 2, column 1`) {
+		t.Errorf("expected the invalid token to be located, got:\n%s", out)
+	}
+
+	nums := filepath.Join(t.TempDir(), "nums.txt")
+	if err := os.WriteFile(nums, []byte("5 2\n7 2.0 9\n"), 0644); err != nil {
+		t.Fatalf("failed to write numbers: %v", err)
+	}
+
+	cmd := exec.Command(checkerPath, "-json", "-files", "-,"+nums)
+	cmd.Stdin = strings.NewReader("")
+	stdout, _ := cmd.Output()
+
+	var rep struct {
+		Error       string `json:"error"`
+		ErrorIndex  *int   `json:"error_index"`
+		ErrorToken  string `json:"error_token"`
+		DuplicateOf *int   `json:"duplicate_of"`
+	}
+	if err := json.Unmarshal(stdout, &rep); err != nil {
+		t.Fatalf("invalid report %q: %v", stdout, err)
+	}
+
+	if rep.ErrorIndex == nil || *rep.ErrorIndex != 3 || rep.ErrorToken != "2.0" || rep.DuplicateOf == nil || *rep.DuplicateOf != 1 {
+		t.Errorf("report = %+v, want the duplicate 2.0 at index 3 of the number at index 1", rep)
+	}
+	if strings.Contains(rep.Error, "\n") {
+		t.Errorf("expected the report error without highlighting, got %q", rep.Error)
+	}
+}
+
+func TestLongLines(t *testing.T) {
+	pushSwapPath, checkerPath := buildBinaries(t)
+
+	// Sorted numbers on a single line well over the 64 KiB a bufio.Scanner
+	// accepts by default.
+	numbers := make([]string, 15000)
+	for i := range numbers {
+		numbers[i] = strconv.Itoa(i * 1000003)
+	}
+
+	line := strings.Join(numbers, " ")
+	if len(line) <= 64*1024 {
+		t.Fatalf("line of %d bytes is too short", len(line))
+	}
+
+	tmp := t.TempDir()
+	nums := filepath.Join(tmp, "nums.txt")
+	inst := filepath.Join(tmp, "inst.txt")
+	if err := os.WriteFile(nums, []byte(line+"\n"), 0644); err != nil {
+		t.Fatalf("failed to write numbers: %v", err)
+	}
+	if err := os.WriteFile(inst, nil, 0644); err != nil {
+		t.Fatalf("failed to write instructions: %v", err)
+	}
+
+	pushSwap := exec.Command(pushSwapPath)
+	pushSwap.Stdin = strings.NewReader(line)
+	out, err := pushSwap.CombinedOutput()
+	if err != nil || len(out) != 0 {
+		t.Errorf("push-swap = %q, %v, want no instructions", out, err)
+	}
+
+	out, err = exec.Command(checkerPath, "-files", inst+","+nums).CombinedOutput()
+	if err != nil || string(out) != "OK\n" {
+		t.Errorf("checker = %q, %v, want OK", out, err)
+	}
+
+	// Errors in files locate the offending token.
+	pushSwap = exec.Command(pushSwapPath)
+	pushSwap.Stdin = strings.NewReader("1 2\n3 abc\n")
+	out, _ = pushSwap.CombinedOutput()
+	if !strings.Contains(string(out), `error parsing "abc" at line 2, column 3`) {
+		t.Errorf("expected the error to locate the token, got %q", out)
+	}
+}
+
+func TestInputFormats(t *testing.T) {
+	pushSwapPath, checkerPath := buildBinaries(t)
+	tmp := t.TempDir()
+
+	tests := []struct {
+		name    string
+		file    string
+		content string
+		args    []string
+	}{
+		{name: "json", file: "nums.json", content: "[5, 1,\n 3, 2, 4]\n"},
+		{name: "csv column", file: "nums.csv", content: "5\n1\n3\n2\n4\n"},
+		{name: "csv row", file: "nums.csv", content: "5,1,3,2,4\n"},
+		{name: "explicit format", file: "nums.txt", content: "[5, 1, 3, 2, 4]", args: []string{"-input-format", "json"}},
+		// Integers that collide as floats keep their digits in JSON.
+		{name: "json bigint", file: "ids.json", content: "[9007199254740993, 9007199254740992, 1]", args: []string{"-bigint"}},
+		{name: "json strings", file: "versions.json", content: `["1.10", "1.2", "1.9"]`, args: []string{"-type", "version"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := filepath.Join(tmp, strings.ReplaceAll(tt.name, " ", "-"))
+			if err := os.Mkdir(dir, 0755); err != nil {
+				t.Fatalf("failed to create directory: %v", err)
+			}
+
+			nums := filepath.Join(dir, tt.file)
+			ops := filepath.Join(dir, "ops.txt")
+			if err := os.WriteFile(nums, []byte(tt.content), 0644); err != nil {
+				t.Fatalf("failed to write numbers: %v", err)
+			}
+
+			out, err := exec.Command(pushSwapPath, append(tt.args, "-files", nums+","+ops)...).CombinedOutput()
+			if err != nil || len(out) != 0 {
+				t.Fatalf("push-swap = %q, %v", out, err)
+			}
+
+			out, err = exec.Command(checkerPath, append(tt.args, "-files", ops+","+nums)...).CombinedOutput()
+			if err != nil || string(out) != "OK\n" {
+				t.Errorf("checker = %q, %v, want OK", out, err)
+			}
+		})
+	}
+
+	pushSwap := exec.Command(pushSwapPath, "-input-format", "json")
+	pushSwap.Stdin = strings.NewReader("[3, 1 2]")
+	out, _ := pushSwap.CombinedOutput()
+	if !strings.Contains(string(out), "invalid JSON at line 1, column 7: expected ',' or ']'") {
+		t.Errorf("expected the JSON error to be located, got %q", out)
+	}
+
+	out, _ = exec.Command(pushSwapPath, "-input-format", "yaml").CombinedOutput()
+	if !strings.Contains(string(out), `unknown input format "yaml"`) {
+		t.Errorf("expected an unknown format error, got %q", out)
+	}
+}
+
+func TestOutputFormatJSON(t *testing.T) {
+	pushSwapPath, checkerPath := buildBinaries(t)
+
+	type result struct {
+		Input        []float64 `json:"input"`
+		Algorithm    string    `json:"algorithm"`
+		Instructions []string  `json:"instructions"`
+		Count        int       `json:"count"`
+		DurationNS   *int64    `json:"duration_ns"`
+	}
+
+	out, err := exec.Command(pushSwapPath, "-output-format", "json", "5", "1", "4", "2", "3").Output()
+	if err != nil {
+		t.Fatalf("push-swap failed: %v", err)
+	}
+
+	var res result
+	if err := json.Unmarshal(out, &res); err != nil {
+		t.Fatalf("invalid result %q: %v", out, err)
+	}
+
+	if fmt.Sprint(res.Input) != "[5 1 4 2 3]" || res.Algorithm != "turk" || res.Count != len(res.Instructions) || res.DurationNS == nil {
+		t.Errorf("result = %+v", res)
+	}
+
+	checker := exec.Command(checkerPath, "5", "1", "4", "2", "3")
+	checker.Stdin = strings.NewReader(strings.Join(res.Instructions, "\n") + "\n")
+	if status, _ := checker.Output(); string(status) != "OK\n" {
+		t.Errorf("checker = %q, want OK for the instructions of the result", status)
+	}
+
+	// Every input file gets its own result.
+	tmp := t.TempDir()
+	for i, content := range []string{"2 1", "3 1 2"} {
+		nums := filepath.Join(tmp, fmt.Sprintf("%d.nums", i))
+		if err := os.WriteFile(nums, []byte(content), 0644); err != nil {
+			t.Fatalf("failed to write numbers: %v", err)
+		}
+	}
+
+	out, err = exec.Command(pushSwapPath, "-output-format", "json", "-input-dir", tmp).CombinedOutput()
+	if err != nil {
+		t.Fatalf("push-swap failed: %v: %s", err, out)
+	}
+
+	for i, want := range []string{"[2 1]", "[3 1 2]"} {
+		data, err := os.ReadFile(filepath.Join(tmp, fmt.Sprintf("%d.ops", i)))
+		if err != nil {
+			t.Fatalf("failed to read the result: %v", err)
+		}
+
+		var res result
+		if err := json.Unmarshal(data, &res); err != nil || fmt.Sprint(res.Input) != want {
+			t.Errorf("result %d = %+v, %v, want the input %s", i, res, err, want)
+		}
+	}
+
+	out, _ = exec.Command(pushSwapPath, "-output-format", "yaml", "1").CombinedOutput()
+	if !strings.Contains(string(out), `unknown -output-format "yaml"`) {
+		t.Errorf("expected an unknown format error, got %q", out)
+	}
+
+	out, _ = exec.Command(pushSwapPath, "-output-format", "json", "-explain", "json", "1").CombinedOutput()
+	if !strings.Contains(string(out), "cannot be combined with -explain") {
+		t.Errorf("expected an error combining -output-format json with -explain, got %q", out)
+	}
+}
+
+func TestNonFiniteJSON(t *testing.T) {
+	pushSwapPath, checkerPath := buildBinaries(t)
+
+	// JSON has no NaN or infinities, so they are written as strings.
+	out, err := exec.Command(pushSwapPath, "-number-mode", "any", "-output-format", "json", "1", "NaN", "-Inf").Output()
+	if err != nil {
+		t.Fatalf("push-swap failed: %v", err)
+	}
+
+	var res struct {
+		Input []any `json:"input"`
+	}
+	if err := json.Unmarshal(out, &res); err != nil {
+		t.Fatalf("invalid result %q: %v", out, err)
+	}
+
+	if fmt.Sprint(res.Input) != "[1 NaN -Inf]" {
+		t.Errorf("input = %v, want [1 NaN -Inf]", res.Input)
+	}
+
+	// The input is read back as it was written.
+	input, _ := json.Marshal(res.Input)
+	pushSwap := exec.Command(pushSwapPath, "-number-mode", "any", "-input-format", "json")
+	pushSwap.Stdin = bytes.NewReader(input)
+	if out, err := pushSwap.CombinedOutput(); err != nil || strings.Contains(string(out), "ERROR") {
+		t.Errorf("push-swap -input-format json %s = %q, %v", input, out, err)
+	}
+
+	checker := exec.Command(checkerPath, "-json", "-number-mode", "any", "1", "NaN", "+Inf")
+	checker.Stdin = strings.NewReader("pb\n")
+	stdout, _ := checker.Output()
+
+	var rep struct {
+		Verdict string `json:"verdict"`
+		FinalA  []any  `json:"final_a"`
+	}
+	if err := json.Unmarshal(stdout, &rep); err != nil {
+		t.Fatalf("invalid report %q: %v", stdout, err)
+	}
+
+	if rep.Verdict != "KO" || fmt.Sprint(rep.FinalA) != "[NaN +Inf]" {
+		t.Errorf("report = %+v, want KO with NaN and +Inf left in A", rep)
+	}
+}
+
+func TestBigInt(t *testing.T) {
+	pushSwapPath, checkerPath := buildBinaries(t)
+
+	// 64-bit IDs that are equal or out of order once rounded to float64.
+	ids := []string{
+		"9223372036854775807",
+		"9223372036854775806",
+		"-9223372036854775808",
+		"18446744073709551615",
+		"9007199254740993",
+		"9007199254740992",
+		"123456789012345678901234567890",
+		"0",
+	}
+
+	pushSwap := exec.Command(pushSwapPath)
+	pushSwap.Stdin = strings.NewReader(strings.Join(ids, " "))
+	out, _ := pushSwap.CombinedOutput()
+	if !strings.Contains(string(out), "duplicate number") {
+		t.Errorf("expected the IDs to collide as floats, got %q", out)
+	}
+
+	instructions, err := exec.Command(pushSwapPath, append([]string{"-bigint", "--"}, ids...)...).Output()
+	if err != nil {
+		t.Fatalf("push-swap -bigint failed: %v", err)
+	}
+
+	checker := exec.Command(checkerPath, append([]string{"-compat", "-bigint", "--"}, ids...)...)
+	checker.Stdin = bytes.NewReader(instructions)
+	if status, _ := checker.Output(); string(status) != "OK\n" {
+		t.Errorf("checker -bigint = %q, want OK", status)
+	}
+
+	// Reports hold the integers in decimal.
+	checker = exec.Command(checkerPath, append([]string{"-json", "-bigint", "--"}, ids[:3]...)...)
+	checker.Stdin = strings.NewReader("pb\n")
+	stdout, _ := checker.Output()
+
+	var rep struct {
+		Verdict string   `json:"verdict"`
+		FinalA  []string `json:"final_a"`
+		FinalB  []string `json:"final_b"`
+	}
+	if err := json.Unmarshal(stdout, &rep); err != nil {
+		t.Fatalf("invalid report %q: %v", stdout, err)
+	}
+
+	if rep.Verdict != "KO" || strings.Join(rep.FinalA, " ") != strings.Join(ids[1:3], " ") || strings.Join(rep.FinalB, " ") != ids[0] {
+		t.Errorf("report = %+v, want KO with the IDs in decimal", rep)
+	}
+
+	pushSwap = exec.Command(pushSwapPath, "-bigint")
+	pushSwap.Stdin = strings.NewReader("1 2.5")
+	out, _ = pushSwap.CombinedOutput()
+	if !strings.Contains(string(out), `error parsing "2.5" at line 1, column 3: not an integer`) {
+		t.Errorf("expected an error for a float with -bigint, got %q", out)
+	}
+}
+
+func TestValueTypes(t *testing.T) {
+	pushSwapPath, checkerPath := buildBinaries(t)
+
+	tests := []struct {
+		valueType string
+		values    []string
+		// Instructions that sort the values only under this type.
+		sorts string
+	}{
+		{"int", []string{"-9223372036854775808", "42", "7", "-3", "9223372036854775807"}, ""},
+		{"string", []string{"pear", "Apple", "fig", "apple", "banana", "10", "9"}, ""},
+		{"version", []string{"1.10", "1.2", "v1.9", "2.0.0", "1.2.1", "0.9"}, ""},
+		// As floats 9 sorts before 10, as strings it does not.
+		{"string", []string{"9", "10"}, "sa\n"},
+		{"version", []string{"1.10", "1.9"}, "sa\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.valueType+" "+strings.Join(tt.values, " "), func(t *testing.T) {
+			args := append([]string{"-type", tt.valueType, "--"}, tt.values...)
+
+			instructions := tt.sorts
+			if instructions == "" {
+				out, err := exec.Command(pushSwapPath, args...).CombinedOutput()
+				if err != nil || strings.Contains(string(out), "ERROR") {
+					t.Fatalf("push-swap -type %s failed: %v\n%s", tt.valueType, err, out)
+				}
+
+				instructions = string(out)
+			}
+
+			checker := exec.Command(checkerPath, append([]string{"-compat"}, args...)...)
+			checker.Stdin = strings.NewReader(instructions)
+			if status, _ := checker.Output(); string(status) != "OK\n" {
+				t.Errorf("checker -type %s = %q, want OK", tt.valueType, status)
+			}
+		})
+	}
+
+	t.Run("explanation holds the values", func(t *testing.T) {
+		out, err := exec.Command(pushSwapPath, "-type", "version", "-explain", "json", "--", "v2.1", "1.10", "1.9", "3", "0.1").Output()
+		if err != nil {
+			t.Fatalf("push-swap -explain json failed: %v", err)
+		}
+
+		var explanation struct {
+			Steps []struct {
+				Move *struct {
+					Value string `json:"Value"`
+				} `json:"Move"`
+			} `json:"steps"`
+		}
+		if err := json.Unmarshal(out, &explanation); err != nil {
+			t.Fatalf("invalid explanation %q: %v", out, err)
+		}
+
+		var values []string
+		for _, step := range explanation.Steps {
+			if step.Move != nil {
+				values = append(values, step.Move.Value)
+			}
+		}
+
+		if !strings.Contains(strings.Join(values, " "), "1.") {
+			t.Errorf("expected versions in the moves, got %v", values)
+		}
+	})
+
+	t.Run("invalid values", func(t *testing.T) {
+		for valueType, value := range map[string]string{"int": "1.5", "version": "1.x", "bigint": "1e3"} {
+			pushSwap := exec.Command(pushSwapPath, "-type", valueType)
+			pushSwap.Stdin = strings.NewReader("1 " + value)
+			out, _ := pushSwap.CombinedOutput()
+			if !strings.Contains(string(out), fmt.Sprintf("error parsing %q", value)) {
+				t.Errorf("push-swap -type %s %s = %q, want a parse error", valueType, value, out)
+			}
+		}
+
+		out, err := exec.Command(checkerPath, "-type", "complex", "1").CombinedOutput()
+		if err == nil || !strings.Contains(string(out), `unknown -type "complex"`) {
+			t.Errorf("checker -type complex = %q, %v, want an error", out, err)
+		}
+	})
+}